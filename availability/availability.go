@@ -7,10 +7,14 @@ import (
 	"github.com/Melpic13/timeslot/slot"
 )
 
-// Availability combines weekly schedule with exceptions.
+// Availability combines weekly schedule with exceptions. Versions, when
+// set, replace Weekly on the dates they cover. Holidays are resolved per
+// day and are not part of the JSON encoding.
 type Availability struct {
 	Weekly     WeeklySchedule
+	Versions   []ScheduleVersion
 	Exceptions ExceptionSet
+	Holidays   HolidayCalendar
 	Bookings   slot.SlotCollection
 	Location   *time.Location
 }
@@ -37,6 +41,21 @@ func (a Availability) AddAvailableOverride(date time.Time, ranges ...TimeRange)
 	return a
 }
 
+func (a Availability) AddAvailableRange(start, end time.Time) Availability {
+	a.Exceptions = a.Exceptions.AddAvailableRange(start, end)
+	return a
+}
+
+func (a Availability) AddExtraHours(date time.Time, ranges ...TimeRange) Availability {
+	a.Exceptions = a.Exceptions.AddExtraHours(date, ranges...)
+	return a
+}
+
+func (a Availability) RemoveHours(date time.Time, ranges ...TimeRange) Availability {
+	a.Exceptions = a.Exceptions.RemoveHours(date, ranges...)
+	return a
+}
+
 func (a Availability) AddBooking(s slot.TimeSlot) Availability {
 	a.Bookings = a.Bookings.Add(s)
 	return a
@@ -55,41 +74,9 @@ func (a Availability) GetSlots(from, to time.Time) slot.SlotCollection {
 	from = from.In(loc)
 	to = to.In(loc)
 
-	var generated []slot.TimeSlot
-	for day := timeutil.StartOfDay(from, loc); day.Before(to) || day.Equal(to); day = day.Add(24 * time.Hour) {
-		dayStart := timeutil.StartOfDay(day, loc)
-		dayEnd := dayStart.Add(24 * time.Hour)
-		if dayEnd.Before(from) || dayStart.After(to) {
-			continue
-		}
-
-		ranges, modified := a.Exceptions.ModifiedForDate(dayStart)
-		if modified {
-			for _, r := range ranges {
-				start := r.Start.ToTime(dayStart, loc)
-				end := r.End.ToTime(dayStart, loc)
-				start = timeutil.Clamp(start, from, to)
-				end = timeutil.Clamp(end, from, to)
-				if end.After(start) {
-					generated = append(generated, slot.TimeSlot{Start: start, End: end, Location: loc})
-				}
-			}
-			continue
-		}
-
-		weeklyRanges := a.Weekly.GetDay(dayStart.Weekday())
-		for _, r := range weeklyRanges {
-			start := r.Start.ToTime(dayStart, loc)
-			end := r.End.ToTime(dayStart, loc)
-			start = timeutil.Clamp(start, from, to)
-			end = timeutil.Clamp(end, from, to)
-			if end.After(start) {
-				generated = append(generated, slot.TimeSlot{Start: start, End: end, Location: loc})
-			}
-		}
-	}
-
-	base := slot.NewCollection(generated...)
+	// The precedence of exceptions is documented on ExceptionSet.
+	base, extra, removed := a.scheduled(from, to)
+	base = base.Union(extra).Merge().Subtract(removed)
 
 	for _, blocked := range a.Exceptions.Blocked {
 		base = base.Remove(slot.TimeSlot{Start: blocked.Start, End: blocked.End, Location: loc})
@@ -111,6 +98,55 @@ func (a Availability) GetSlots(from, to time.Time) slot.SlotCollection {
 	return base.Merge()
 }
 
+// scheduled returns, clamped to [from, to), the regular ranges of every day
+// along with the extra and removed hours recorded for those days.
+func (a Availability) scheduled(from, to time.Time) (base, extra, removed slot.SlotCollection) {
+	loc := a.locationOrUTC()
+	var b, e, r []slot.TimeSlot
+	clamped := func(dst []slot.TimeSlot, day time.Time, ranges []TimeRange) []slot.TimeSlot {
+		for _, tr := range ranges {
+			rs := tr.SlotOn(day, loc)
+			start := timeutil.Clamp(rs.Start, from, to)
+			end := timeutil.Clamp(rs.End, from, to)
+			if end.After(start) {
+				dst = append(dst, slot.TimeSlot{Start: start, End: end, Location: loc})
+			}
+		}
+		return dst
+	}
+	holidays := a.holidayIndex()
+	// Start a day early so overnight ranges spilling into from are included.
+	for dayStart := timeutil.AddDays(from, -1, loc); !dayStart.After(to); dayStart = timeutil.AddDays(dayStart, 1, loc) {
+		ranges, _ := a.dayRanges(dayStart, holidays)
+		b = clamped(b, dayStart, ranges)
+		e = clamped(e, dayStart, a.Exceptions.ExtraForDate(dayStart))
+		r = clamped(r, dayStart, a.Exceptions.RemovedForDate(dayStart))
+	}
+	return slot.NewCollection(b...).Merge(), slot.NewCollection(e...).Merge(), slot.NewCollection(r...).Merge()
+}
+
+// dayRanges returns the regular ranges of day and, when they do not come
+// from the weekly schedule, the reason they were replaced. holidays is nil
+// when there is no holiday calendar.
+func (a Availability) dayRanges(day time.Time, holidays *holidayIndex) ([]TimeRange, *Reason) {
+	if ranges, ok := a.Exceptions.ModifiedForDate(day); ok {
+		return ranges, &Reason{Code: ReasonModifiedDay, Message: "hours modified for " + day.Format("2006-01-02")}
+	}
+	if holidays != nil {
+		if h, ok := holidays.on(day); ok {
+			return h.Hours, &Reason{Code: ReasonHoliday, Message: h.Name}
+		}
+	}
+	return a.ScheduleFor(day).GetDay(day.Weekday()), nil
+}
+
+func (a Availability) holidayIndex() *holidayIndex {
+	if a.Holidays.IsZero() {
+		return nil
+	}
+	return a.Holidays.index(a.locationOrUTC())
+}
+
 func (a Availability) IsAvailable(t time.Time) bool {
 	probe := slot.TimeSlot{Start: t, End: t.Add(time.Second), Location: a.locationOrUTC()}
 	return len(a.GetSlots(t.Add(-24*time.Hour), t.Add(24*time.Hour)).FindOverlaps(probe)) > 0
@@ -121,24 +157,20 @@ func (a Availability) IsBooked(t time.Time) bool {
 	return len(a.Bookings.FindOverlaps(probe)) > 0
 }
 
-func (a Availability) FindAvailableSlots(duration time.Duration, from, to time.Time) []slot.TimeSlot {
+func (a Availability) FindAvailableSlots(duration time.Duration, from, to time.Time, opts ...slot.GeneratorOption) []slot.TimeSlot {
 	if duration <= 0 {
 		return nil
 	}
-	free := a.GetSlots(from, to)
-	var out []slot.TimeSlot
-	for _, s := range free.Slots() {
-		for cur := s.Start; cur.Add(duration).Before(s.End) || cur.Add(duration).Equal(s.End); cur = cur.Add(duration) {
-			out = append(out, slot.TimeSlot{Start: cur, End: cur.Add(duration), Location: s.Location})
-		}
-	}
-	return out
+	return slot.NewGenerator(duration, opts...).Generate(a.GetSlots(from, to))
 }
 
 func (a Availability) Validate() error {
 	if err := a.Weekly.Validate(); err != nil {
 		return err
 	}
+	if err := validateVersions(a.Versions); err != nil {
+		return err
+	}
 	if err := a.Exceptions.Validate(); err != nil {
 		return err
 	}
@@ -30,3 +30,37 @@ func TestFindAvailableSlots(t *testing.T) {
 		t.Fatalf("expected 2, got %d", len(got))
 	}
 }
+
+func TestFindAvailableSlotsWithStep(t *testing.T) {
+	base := New(time.UTC)
+	base.Weekly = base.Weekly.SetDay(time.Monday, TimeRange{Start: NewTimeOfDay(9, 0, 0), End: NewTimeOfDay(11, 30, 0)})
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	got := base.FindAvailableSlots(time.Hour, from, from.Add(24*time.Hour), slot.WithStep(15*time.Minute))
+	if len(got) != 7 {
+		t.Fatalf("expected 7, got %d", len(got))
+	}
+}
+
+func TestGetSlotsOvernightSupportDesk(t *testing.T) {
+	base := New(time.UTC)
+	for _, d := range []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday} {
+		base.Weekly = base.Weekly.SetDay(d,
+			TimeRange{Start: NewTimeOfDay(8, 0, 0), End: NewTimeOfDay(16, 0, 0)},
+			TimeRange{Start: NewTimeOfDay(16, 0, 0), End: NewTimeOfDay(8, 0, 0), Overnight: true},
+		)
+	}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	free := base.GetSlots(from, to)
+	if free.Len() != 1 || free.TotalDuration() != 7*24*time.Hour {
+		t.Fatalf("expected contiguous 24/7 coverage, got %d slots (%v)", free.Len(), free.TotalDuration())
+	}
+
+	override := base.AddAvailableOverride(from.AddDate(0, 0, 2), TimeRange{Start: NewTimeOfDay(22, 0, 0), End: NewTimeOfDay(2, 0, 0), Overnight: true})
+	// Tuesday's shift still spills into Wednesday 00:00-08:00; Wednesday
+	// itself is replaced by 22:00-02:00.
+	day := override.GetSlots(from.AddDate(0, 0, 2), from.AddDate(0, 0, 3).Add(8*time.Hour))
+	if day.TotalDuration() != 8*time.Hour+4*time.Hour {
+		t.Fatalf("unexpected override coverage %v", day.TotalDuration())
+	}
+}
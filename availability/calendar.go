@@ -0,0 +1,155 @@
+package availability
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Melpic13/timeslot/internal/errs"
+	"github.com/Melpic13/timeslot/internal/timeutil"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// ErrNoWorkingTime is returned when calendar arithmetic runs past the search
+// horizon without finding any working time.
+var ErrNoWorkingTime = fmt.Errorf("%w: no working time within search horizon", errs.ErrNoAvailability)
+
+const (
+	// workingTimeHorizon is the longest stretch without working time that
+	// calendar arithmetic will scan before giving up.
+	workingTimeHorizon = 366 * 24 * time.Hour
+	workingTimeChunk   = 7 * 24 * time.Hour
+	workingDayHorizon  = 366
+)
+
+// AddWorkingTime returns the instant reached after spending d of working
+// time from t, following the weekly schedule and exceptions. Bookings do
+// not consume working time. A negative d walks backwards.
+func (a Availability) AddWorkingTime(t time.Time, d time.Duration) (time.Time, error) {
+	if d == 0 {
+		return t, nil
+	}
+	cal := a.workingCalendar()
+	if d < 0 {
+		return cal.subtractWorkingTime(t, -d)
+	}
+	remaining := d
+	cursor := t
+	for idle := time.Duration(0); idle < workingTimeHorizon; {
+		next := cursor.Add(workingTimeChunk)
+		free := cal.GetSlots(cursor, next)
+		if free.IsEmpty() {
+			idle += workingTimeChunk
+			cursor = next
+			continue
+		}
+		for _, s := range free.Slots() {
+			if s.Duration() >= remaining {
+				return s.Start.Add(remaining), nil
+			}
+			remaining -= s.Duration()
+		}
+		idle = 0
+		cursor = next
+	}
+	return time.Time{}, ErrNoWorkingTime
+}
+
+func (a Availability) subtractWorkingTime(t time.Time, d time.Duration) (time.Time, error) {
+	remaining := d
+	cursor := t
+	for idle := time.Duration(0); idle < workingTimeHorizon; {
+		prev := cursor.Add(-workingTimeChunk)
+		free := a.GetSlots(prev, cursor).Slots()
+		if len(free) == 0 {
+			idle += workingTimeChunk
+			cursor = prev
+			continue
+		}
+		for i := len(free) - 1; i >= 0; i-- {
+			if free[i].Duration() >= remaining {
+				return free[i].End.Add(-remaining), nil
+			}
+			remaining -= free[i].Duration()
+		}
+		idle = 0
+		cursor = prev
+	}
+	return time.Time{}, ErrNoWorkingTime
+}
+
+// WorkingTimeBetween returns the working time between from and to. The
+// result is negative when to is before from.
+func (a Availability) WorkingTimeBetween(from, to time.Time) time.Duration {
+	if to.Before(from) {
+		return -a.WorkingTimeBetween(to, from)
+	}
+	return a.workingCalendar().GetSlots(from, to).TotalDuration()
+}
+
+// IsWorkingDay reports whether the calendar date of day, in the
+// availability location, has any working time.
+func (a Availability) IsWorkingDay(day time.Time) bool {
+	return a.workingCalendar().isWorkingDay(day)
+}
+
+// AddBusinessDays moves t by n working days, keeping its local time of day.
+// Days without any working time are skipped. A negative n walks backwards.
+func (a Availability) AddBusinessDays(t time.Time, n int) (time.Time, error) {
+	if n == 0 {
+		return t, nil
+	}
+	cal := a.workingCalendar()
+	loc := cal.locationOrUTC()
+	local := t.In(loc)
+	dir := 1
+	if n < 0 {
+		dir = -1
+		n = -n
+	}
+	for i, idle := 1, 0; idle < workingDayHorizon; i++ {
+		day := timeutil.AddDays(local, dir*i, loc)
+		if !cal.isWorkingDay(day) {
+			idle++
+			continue
+		}
+		idle = 0
+		n--
+		if n == 0 {
+			y, m, d := day.Date()
+			return timeutil.Date(y, m, d, local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), loc), nil
+		}
+	}
+	return time.Time{}, ErrNoWorkingTime
+}
+
+// BusinessDaysBetween counts working days after from's date up to and
+// including to's date. The result is negative when to is before from.
+func (a Availability) BusinessDaysBetween(from, to time.Time) int {
+	cal := a.workingCalendar()
+	loc := cal.locationOrUTC()
+	start := timeutil.StartOfDay(from, loc)
+	end := timeutil.StartOfDay(to, loc)
+	if end.Before(start) {
+		return -a.BusinessDaysBetween(to, from)
+	}
+	count := 0
+	for day := timeutil.AddDays(start, 1, loc); !day.After(end); day = timeutil.AddDays(day, 1, loc) {
+		if cal.isWorkingDay(day) {
+			count++
+		}
+	}
+	return count
+}
+
+func (a Availability) isWorkingDay(day time.Time) bool {
+	loc := a.locationOrUTC()
+	start := timeutil.StartOfDay(day, loc)
+	return !a.GetSlots(start, timeutil.AddDays(start, 1, loc)).IsEmpty()
+}
+
+// workingCalendar returns a copy of the availability without bookings, so
+// that existing appointments still count as working time.
+func (a Availability) workingCalendar() Availability {
+	a.Bookings = slot.NewCollection()
+	return a
+}
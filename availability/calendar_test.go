@@ -0,0 +1,120 @@
+package availability
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/slot"
+)
+
+func businessWeek() Availability {
+	a := New(time.UTC)
+	for _, d := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday} {
+		a.Weekly = a.Weekly.SetDay(d, TimeRange{Start: NewTimeOfDay(9, 0, 0), End: NewTimeOfDay(17, 0, 0)})
+	}
+	return a
+}
+
+func TestAddWorkingTime(t *testing.T) {
+	a := businessWeek()
+	friday := time.Date(2025, 1, 10, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		from time.Time
+		d    time.Duration
+		want time.Time
+	}{
+		{"same day", friday, time.Hour, time.Date(2025, 1, 10, 16, 0, 0, 0, time.UTC)},
+		{"ends exactly at close", friday, 2 * time.Hour, time.Date(2025, 1, 10, 17, 0, 0, 0, time.UTC)},
+		{"over weekend", friday, 8 * time.Hour, time.Date(2025, 1, 13, 15, 0, 0, 0, time.UTC)},
+		{"before opening", time.Date(2025, 1, 6, 6, 0, 0, 0, time.UTC), 30 * time.Minute, time.Date(2025, 1, 6, 9, 30, 0, 0, time.UTC)},
+		{"backwards over weekend", time.Date(2025, 1, 13, 10, 0, 0, 0, time.UTC), -2 * time.Hour, time.Date(2025, 1, 10, 16, 0, 0, 0, time.UTC)},
+		{"zero", friday, 0, friday},
+	}
+	for _, tt := range tests {
+		got, err := a.AddWorkingTime(tt.from, tt.d)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Fatalf("%s: got %v want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAddWorkingTimeIgnoresBookingsAndHonorsExceptions(t *testing.T) {
+	a := businessWeek()
+	monday := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	a = a.AddBooking(slot.TimeSlot{Start: monday, End: monday.Add(4 * time.Hour), Location: time.UTC})
+	got, err := a.AddWorkingTime(monday, time.Hour)
+	if err != nil || !got.Equal(monday.Add(time.Hour)) {
+		t.Fatalf("bookings should not consume working time: %v %v", got, err)
+	}
+
+	a = a.AddBlockedDates(monday)
+	got, err = a.AddWorkingTime(monday, time.Hour)
+	if err != nil || !got.Equal(time.Date(2025, 1, 7, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("blocked day should be skipped: %v %v", got, err)
+	}
+}
+
+func TestWorkingTimeNoSchedule(t *testing.T) {
+	a := New(time.UTC)
+	from := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	if _, err := a.AddWorkingTime(from, time.Hour); !errors.Is(err, ErrNoWorkingTime) {
+		t.Fatalf("expected ErrNoWorkingTime, got %v", err)
+	}
+	if _, err := a.AddWorkingTime(from, -time.Hour); !errors.Is(err, ErrNoWorkingTime) {
+		t.Fatalf("expected ErrNoWorkingTime backwards, got %v", err)
+	}
+	if _, err := a.AddBusinessDays(from, 1); !errors.Is(err, ErrNoWorkingTime) {
+		t.Fatalf("expected ErrNoWorkingTime for business days, got %v", err)
+	}
+}
+
+func TestWorkingTimeBetween(t *testing.T) {
+	a := businessWeek()
+	from := time.Date(2025, 1, 10, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 13, 10, 0, 0, 0, time.UTC)
+	if got := a.WorkingTimeBetween(from, to); got != 3*time.Hour {
+		t.Fatalf("expected 3h, got %v", got)
+	}
+	if got := a.WorkingTimeBetween(to, from); got != -3*time.Hour {
+		t.Fatalf("expected -3h, got %v", got)
+	}
+}
+
+func TestBusinessDays(t *testing.T) {
+	a := businessWeek()
+	friday := time.Date(2025, 1, 10, 14, 30, 0, 0, time.UTC)
+	if !a.IsWorkingDay(friday) || a.IsWorkingDay(friday.AddDate(0, 0, 1)) {
+		t.Fatalf("working day detection failed")
+	}
+
+	got, err := a.AddBusinessDays(friday, 3)
+	if err != nil || !got.Equal(time.Date(2025, 1, 15, 14, 30, 0, 0, time.UTC)) {
+		t.Fatalf("add 3 business days: %v %v", got, err)
+	}
+	got, err = a.AddBusinessDays(friday, -5)
+	if err != nil || !got.Equal(time.Date(2025, 1, 3, 14, 30, 0, 0, time.UTC)) {
+		t.Fatalf("subtract 5 business days: %v %v", got, err)
+	}
+	if got, _ := a.AddBusinessDays(friday, 0); !got.Equal(friday) {
+		t.Fatalf("zero business days should be identity")
+	}
+
+	withHoliday := a.AddBlockedDates(time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC))
+	got, err = withHoliday.AddBusinessDays(friday, 3)
+	if err != nil || !got.Equal(time.Date(2025, 1, 16, 14, 30, 0, 0, time.UTC)) {
+		t.Fatalf("blocked day should be skipped: %v %v", got, err)
+	}
+
+	if n := a.BusinessDaysBetween(friday, friday.AddDate(0, 0, 7)); n != 5 {
+		t.Fatalf("expected 5 business days, got %d", n)
+	}
+	if n := a.BusinessDaysBetween(friday.AddDate(0, 0, 7), friday); n != -5 {
+		t.Fatalf("expected -5 business days, got %d", n)
+	}
+}
@@ -0,0 +1,144 @@
+package availability
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// Source is anything that reports free time within a window. Availability
+// and Expr both implement it, so expressions nest.
+type Source interface {
+	GetSlots(from, to time.Time) slot.SlotCollection
+}
+
+type exprOp int
+
+const (
+	opUnion exprOp = iota
+	opIntersection
+	opDifference
+	opAtLeast
+)
+
+// Expr combines several sources. It holds no slots itself; every call to
+// GetSlots evaluates the sources for the requested window only.
+type Expr struct {
+	op      exprOp
+	k       int
+	sources []Source
+}
+
+// Union is free whenever any source is free.
+func Union(sources ...Source) Expr {
+	return Expr{op: opUnion, sources: append([]Source(nil), sources...)}
+}
+
+// Intersection is free only when every source is free. With no sources it
+// is never free.
+func Intersection(sources ...Source) Expr {
+	return Expr{op: opIntersection, sources: append([]Source(nil), sources...)}
+}
+
+// Difference is free when base is free and none of subtract is.
+func Difference(base Source, subtract ...Source) Expr {
+	return Expr{op: opDifference, sources: append([]Source{base}, subtract...)}
+}
+
+// AtLeast is free when at least k of the sources are free at once, e.g.
+// two of three nurses. AtLeast(1, ...) equals Union and AtLeast(n, ...) with
+// n sources equals Intersection.
+func AtLeast(k int, sources ...Source) Expr {
+	return Expr{op: opAtLeast, k: k, sources: append([]Source(nil), sources...)}
+}
+
+func (e Expr) GetSlots(from, to time.Time) slot.SlotCollection {
+	if !to.After(from) || len(e.sources) == 0 {
+		return slot.NewCollection()
+	}
+	switch e.op {
+	case opIntersection:
+		out := e.sources[0].GetSlots(from, to)
+		for _, s := range e.sources[1:] {
+			if out.IsEmpty() {
+				break
+			}
+			out = out.Intersect(s.GetSlots(from, to))
+		}
+		return out
+	case opDifference:
+		out := e.sources[0].GetSlots(from, to)
+		for _, s := range e.sources[1:] {
+			if out.IsEmpty() {
+				break
+			}
+			out = out.Subtract(s.GetSlots(from, to))
+		}
+		return out
+	case opAtLeast:
+		return e.atLeast(from, to)
+	default:
+		out := slot.NewCollection()
+		for _, s := range e.sources {
+			out = out.Union(s.GetSlots(from, to))
+		}
+		return out.Merge()
+	}
+}
+
+// atLeast sweeps over the start and end of every free slot, counting how
+// many sources are free between consecutive boundaries.
+func (e Expr) atLeast(from, to time.Time) slot.SlotCollection {
+	k := e.k
+	if k < 1 {
+		k = 1
+	}
+	if k > len(e.sources) {
+		return slot.NewCollection()
+	}
+	type event struct {
+		at    time.Time
+		delta int
+	}
+	var events []event
+	for _, s := range e.sources {
+		for _, free := range s.GetSlots(from, to).Slots() {
+			events = append(events, event{at: free.Start, delta: 1}, event{at: free.End, delta: -1})
+		}
+	}
+	// Ends sort before starts at the same instant, since slots are half-open.
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].at.Equal(events[j].at) {
+			return events[i].delta < events[j].delta
+		}
+		return events[i].at.Before(events[j].at)
+	})
+	loc := from.Location()
+	var out []slot.TimeSlot
+	count := 0
+	var start time.Time
+	for _, ev := range events {
+		before := count
+		count += ev.delta
+		switch {
+		case before < k && count >= k:
+			start = ev.at
+		case before >= k && count < k && ev.at.After(start):
+			out = append(out, slot.TimeSlot{Start: start, End: ev.at, Location: loc})
+		}
+	}
+	return slot.NewCollection(out...)
+}
+
+func (e Expr) IsAvailable(t time.Time) bool {
+	probe := slot.TimeSlot{Start: t, End: t.Add(time.Second), Location: t.Location()}
+	return len(e.GetSlots(t.Add(-24*time.Hour), t.Add(24*time.Hour)).FindOverlaps(probe)) > 0
+}
+
+func (e Expr) FindAvailableSlots(duration time.Duration, from, to time.Time, opts ...slot.GeneratorOption) []slot.TimeSlot {
+	if duration <= 0 {
+		return nil
+	}
+	return slot.NewGenerator(duration, opts...).Generate(e.GetSlots(from, to))
+}
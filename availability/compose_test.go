@@ -0,0 +1,98 @@
+package availability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/slot"
+)
+
+type countingSource struct {
+	Source
+	calls *int
+}
+
+func (c countingSource) GetSlots(from, to time.Time) slot.SlotCollection {
+	*c.calls++
+	return c.Source.GetSlots(from, to)
+}
+
+func composeFixture(t *testing.T, schedule string) Availability {
+	t.Helper()
+	ws, err := ParseSchedule(schedule)
+	if err != nil {
+		t.Fatalf("parse %q: %v", schedule, err)
+	}
+	a := New(time.UTC)
+	a.Weekly = ws
+	return a
+}
+
+func TestComposeOperators(t *testing.T) {
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return monday.Add(time.Duration(h) * time.Hour) }
+	room := composeFixture(t, "Mon 08:00-16:00")
+	doctor := composeFixture(t, "Mon 10:00-18:00").AddBooking(slot.TimeSlot{Start: at(11), End: at(12), Location: time.UTC})
+	lunch := composeFixture(t, "Mon 12:00-13:00")
+	n1 := composeFixture(t, "Mon 08:00-12:00")
+	n2 := composeFixture(t, "Mon 10:00-14:00")
+	n3 := composeFixture(t, "Mon 11:00-16:00")
+
+	tests := []struct {
+		name string
+		expr Expr
+		want []int
+	}{
+		{"and", Intersection(room, doctor), []int{10, 11, 12, 16}},
+		{"or", Union(n1, n3), []int{8, 16}},
+		{"minus", Difference(room, lunch), []int{8, 12, 13, 16}},
+		{"two of three", AtLeast(2, n1, n2, n3), []int{10, 14}},
+		{"three of three", AtLeast(3, n1, n2, n3), []int{11, 12}},
+		{"one of three", AtLeast(1, n1, n2, n3), []int{8, 16}},
+		{"too many required", AtLeast(4, n1, n2, n3), nil},
+		{"nested", Difference(Intersection(room, AtLeast(2, n1, n2, n3)), lunch), []int{10, 12, 13, 14}},
+		{"empty intersection", Intersection(), nil},
+	}
+	for _, tt := range tests {
+		got := tt.expr.GetSlots(monday, monday.AddDate(0, 0, 1)).Slots()
+		if len(got)*2 != len(tt.want) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+		for i, s := range got {
+			if !s.Start.Equal(at(tt.want[2*i])) || !s.End.Equal(at(tt.want[2*i+1])) {
+				t.Fatalf("%s: slot %d is %s, want %d-%d", tt.name, i, s, tt.want[2*i], tt.want[2*i+1])
+			}
+		}
+	}
+
+	both := Intersection(room, doctor)
+	if !both.IsAvailable(at(10)) || both.IsAvailable(at(11)) {
+		t.Fatalf("unexpected IsAvailable results")
+	}
+	if got := both.FindAvailableSlots(30*time.Minute, monday, monday.AddDate(0, 0, 1)); len(got) != 10 {
+		t.Fatalf("expected 10 half-hour slots, got %d", len(got))
+	}
+	if got := both.FindAvailableSlots(0, monday, monday.AddDate(0, 0, 1)); got != nil {
+		t.Fatalf("expected nil for zero duration")
+	}
+}
+
+func TestComposeIsLazy(t *testing.T) {
+	calls := 0
+	closed := countingSource{Source: composeFixture(t, "Sat 10:00-12:00"), calls: &calls}
+	other := countingSource{Source: composeFixture(t, "Mon 10:00-12:00"), calls: &calls}
+	expr := Intersection(closed, other)
+	if calls != 0 {
+		t.Fatalf("building an expression should not evaluate sources")
+	}
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	if !expr.GetSlots(monday, monday.AddDate(0, 0, 1)).IsEmpty() {
+		t.Fatalf("expected empty intersection")
+	}
+	if calls != 1 {
+		t.Fatalf("intersection should stop once empty, got %d evaluations", calls)
+	}
+	if !expr.GetSlots(monday, monday).IsEmpty() || calls != 1 {
+		t.Fatalf("empty window should not evaluate sources")
+	}
+}
@@ -0,0 +1,133 @@
+package availability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/slot"
+	"github.com/Melpic13/timeslot/timezone"
+)
+
+var dstZones = []string{
+	"America/New_York",
+	"Europe/Berlin",
+	"Australia/Sydney",
+	"Australia/Lord_Howe", // 30-minute shift
+	"America/Santiago",    // transitions at local midnight
+}
+
+func transitionDays(t *testing.T, loc *time.Location, year int) []time.Time {
+	t.Helper()
+	var days []time.Time
+	for d := time.Date(year, 1, 1, 12, 0, 0, 0, loc); d.Year() == year; d = d.AddDate(0, 0, 1) {
+		if timezone.IsDSTTransitionDay(d) {
+			days = append(days, time.Date(d.Year(), d.Month(), d.Day(), 12, 0, 0, 0, loc))
+		}
+	}
+	if len(days) == 0 {
+		t.Fatalf("%s: no transition days found", loc)
+	}
+	return days
+}
+
+func everyDay(loc *time.Location, ranges ...TimeRange) WeeklySchedule {
+	ws := NewWeeklySchedule(loc)
+	for _, d := range []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday} {
+		ws = ws.SetDay(d, ranges...)
+	}
+	return ws
+}
+
+func TestDSTDayIterationVisitsEachDateOnce(t *testing.T) {
+	for _, name := range dstZones {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Skip("timezone DB unavailable")
+		}
+		ws := everyDay(loc, TimeRange{Start: NewTimeOfDay(9, 0, 0), End: NewTimeOfDay(10, 0, 0)})
+		a := New(loc)
+		a.Weekly = ws
+		for _, day := range transitionDays(t, loc, 2024) {
+			from := time.Date(day.Year(), day.Month(), day.Day()-3, 0, 0, 0, 0, loc)
+			to := time.Date(day.Year(), day.Month(), day.Day()+4, 0, 0, 0, 0, loc)
+			for label, slots := range map[string][]time.Time{
+				"GetSlots":      starts(a.GetSlots(from, to).Slots()),
+				"GenerateSlots": starts(ws.GenerateSlots(from, to).Slots()),
+			} {
+				if len(slots) != 7 {
+					t.Fatalf("%s %s around %s: expected 7 daily slots, got %d", name, label, day.Format("2006-01-02"), len(slots))
+				}
+				seen := map[string]bool{}
+				for _, s := range slots {
+					local := s.In(loc)
+					key := local.Format("2006-01-02")
+					if seen[key] || local.Hour() != 9 || local.Minute() != 0 {
+						t.Fatalf("%s %s: unexpected slot start %v", name, label, local)
+					}
+					seen[key] = true
+				}
+			}
+		}
+	}
+}
+
+func TestDSTFullDayCoverageMatchesElapsedTime(t *testing.T) {
+	for _, name := range dstZones {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Skip("timezone DB unavailable")
+		}
+		a := New(loc)
+		a.Weekly = everyDay(loc, TimeRange{Start: NewTimeOfDay(0, 0, 0), End: EndOfDay})
+		for _, day := range transitionDays(t, loc, 2024) {
+			from := a.GetSlots(day.AddDate(0, 0, -2), day.AddDate(0, 0, 2))
+			if from.Len() != 1 {
+				t.Fatalf("%s around %s: expected contiguous coverage, got %d slots", name, day.Format("2006-01-02"), from.Len())
+			}
+			if got, want := from.TotalDuration(), day.AddDate(0, 0, 2).Sub(day.AddDate(0, 0, -2)); got != want {
+				t.Fatalf("%s around %s: covered %v, elapsed %v", name, day.Format("2006-01-02"), got, want)
+			}
+		}
+	}
+}
+
+func TestDSTNonexistentAndAmbiguousScheduleTimes(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("timezone DB unavailable")
+	}
+	springForward := time.Date(2024, 3, 10, 0, 0, 0, 0, ny)
+	ws := NewWeeklySchedule(ny).SetDay(time.Sunday, TimeRange{Start: NewTimeOfDay(2, 30, 0), End: NewTimeOfDay(4, 0, 0)})
+	got := ws.GenerateSlots(springForward, springForward.AddDate(0, 0, 1)).Slots()
+	if len(got) != 1 {
+		t.Fatalf("expected one slot on spring-forward day, got %d", len(got))
+	}
+	// 02:30 does not exist; the range starts when the clocks jump to 03:00.
+	if s := got[0].Start.In(ny); s.Hour() != 3 || s.Minute() != 0 || got[0].Duration() != time.Hour {
+		t.Fatalf("unexpected spring-forward slot %s", got[0])
+	}
+
+	fallBack := time.Date(2024, 11, 3, 0, 0, 0, 0, ny)
+	ws = NewWeeklySchedule(ny).SetDay(time.Sunday, TimeRange{Start: NewTimeOfDay(1, 30, 0), End: NewTimeOfDay(2, 30, 0)})
+	got = ws.GenerateSlots(fallBack, fallBack.AddDate(0, 0, 1)).Slots()
+	if len(got) != 1 {
+		t.Fatalf("expected one slot on fall-back day, got %d", len(got))
+	}
+	// 01:30 happens twice; the range starts at the first occurrence (EDT).
+	if _, offset := got[0].Start.Zone(); offset != -4*3600 || got[0].Duration() != 2*time.Hour {
+		t.Fatalf("unexpected fall-back slot %s (%v)", got[0], got[0].Duration())
+	}
+
+	blocked := New(ny).AddBlockedDates(fallBack)
+	if d := blocked.Exceptions.Blocked[0]; d.End.Sub(d.Start) != 25*time.Hour {
+		t.Fatalf("blocked fall-back day should span 25h, got %v", d.End.Sub(d.Start))
+	}
+}
+
+func starts(slots []slot.TimeSlot) []time.Time {
+	out := make([]time.Time, 0, len(slots))
+	for _, s := range slots {
+		out = append(out, s.Start)
+	}
+	return out
+}
@@ -0,0 +1,245 @@
+package availability
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Melpic13/timeslot/internal/errs"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// EncodingVersion is the version of the JSON wire format written by
+// Availability and WeeklySchedule. Documents without a version are read as
+// the current version.
+const EncodingVersion = 1
+
+//go:embed schema.json
+var jsonSchema []byte
+
+// JSONSchema returns the JSON Schema document describing the encoded
+// Availability format.
+func JSONSchema() []byte {
+	return append([]byte(nil), jsonSchema...)
+}
+
+var weekdayKeys = []struct {
+	key string
+	day time.Weekday
+}{
+	{"monday", time.Monday},
+	{"tuesday", time.Tuesday},
+	{"wednesday", time.Wednesday},
+	{"thursday", time.Thursday},
+	{"friday", time.Friday},
+	{"saturday", time.Saturday},
+	{"sunday", time.Sunday},
+}
+
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return []byte(t.String()), nil
+}
+
+func (t *TimeOfDay) UnmarshalText(text []byte) error {
+	parsed, err := ParseTimeOfDay(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+type weeklyJSON struct {
+	Version  int                    `json:"version"`
+	Timezone string                 `json:"timezone"`
+	Days     map[string][]TimeRange `json:"days"`
+}
+
+func (w WeeklySchedule) MarshalJSON() ([]byte, error) {
+	out := weeklyJSON{Version: EncodingVersion, Timezone: w.locationOrUTC().String(), Days: map[string][]TimeRange{}}
+	for _, wk := range weekdayKeys {
+		if ranges := w.GetDay(wk.day); len(ranges) > 0 {
+			out.Days[wk.key] = ranges
+		}
+	}
+	return json.Marshal(out)
+}
+
+func (w *WeeklySchedule) UnmarshalJSON(data []byte) error {
+	var raw weeklyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if err := checkVersion(raw.Version); err != nil {
+		return err
+	}
+	loc, err := loadLocation(raw.Timezone)
+	if err != nil {
+		return err
+	}
+	out := NewWeeklySchedule(loc)
+	for key, ranges := range raw.Days {
+		day, ok := weekdayNames[strings.ToLower(key)]
+		if !ok {
+			return fmt.Errorf("availability: unknown weekday %q", key)
+		}
+		for _, r := range ranges {
+			if err := r.Validate(); err != nil {
+				return err
+			}
+		}
+		out = out.SetDay(day, ranges...)
+	}
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*w = out
+	return nil
+}
+
+type exceptionsJSON struct {
+	Blocked   []DateRange    `json:"blocked,omitempty"`
+	Available []DateRange    `json:"available,omitempty"`
+	Modified  []DateOverride `json:"modified,omitempty"`
+	Extra     []DateOverride `json:"extra,omitempty"`
+	Removed   []DateOverride `json:"removed,omitempty"`
+}
+
+func (e ExceptionSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(exceptionsJSON(e))
+}
+
+func (e *ExceptionSet) UnmarshalJSON(data []byte) error {
+	var raw exceptionsJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	out := ExceptionSet(raw)
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*e = out
+	return nil
+}
+
+type availabilityJSON struct {
+	Version    int               `json:"version"`
+	Timezone   string            `json:"timezone"`
+	Weekly     WeeklySchedule    `json:"weekly"`
+	Versions   []ScheduleVersion `json:"versions,omitempty"`
+	Exceptions ExceptionSet      `json:"exceptions"`
+	Bookings   []slot.TimeSlot   `json:"bookings"`
+}
+
+func (a Availability) MarshalJSON() ([]byte, error) {
+	bookings := a.Bookings.Slots()
+	if bookings == nil {
+		bookings = []slot.TimeSlot{}
+	}
+	return json.Marshal(availabilityJSON{
+		Version:    EncodingVersion,
+		Timezone:   a.locationOrUTC().String(),
+		Weekly:     a.Weekly,
+		Versions:   a.Versions,
+		Exceptions: a.Exceptions,
+		Bookings:   bookings,
+	})
+}
+
+func (a *Availability) UnmarshalJSON(data []byte) error {
+	var raw availabilityJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if err := checkVersion(raw.Version); err != nil {
+		return err
+	}
+	loc, err := loadLocation(raw.Timezone)
+	if err != nil {
+		return err
+	}
+	if raw.Weekly.Location == nil {
+		raw.Weekly.Location = loc
+	}
+	out := Availability{
+		Weekly:     raw.Weekly,
+		Versions:   raw.Versions,
+		Exceptions: raw.Exceptions,
+		Bookings:   slot.NewCollection(raw.Bookings...),
+		Location:   loc,
+	}
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*a = out
+	return nil
+}
+
+type versionJSON struct {
+	From   string         `json:"from,omitempty"`
+	Until  string         `json:"until,omitempty"`
+	Weekly WeeklySchedule `json:"weekly"`
+}
+
+// dateLayout encodes ScheduleVersion dates; they carry no time or zone.
+const dateLayout = "2006-01-02"
+
+func (v ScheduleVersion) MarshalJSON() ([]byte, error) {
+	out := versionJSON{Weekly: v.Weekly}
+	if !v.From.IsZero() {
+		out.From = v.From.Format(dateLayout)
+	}
+	if !v.Until.IsZero() {
+		out.Until = v.Until.Format(dateLayout)
+	}
+	return json.Marshal(out)
+}
+
+func (v *ScheduleVersion) UnmarshalJSON(data []byte) error {
+	var raw versionJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	out := ScheduleVersion{Weekly: raw.Weekly}
+	for _, f := range []struct {
+		text string
+		dst  *time.Time
+	}{{raw.From, &out.From}, {raw.Until, &out.Until}} {
+		if f.text == "" {
+			continue
+		}
+		d, err := time.Parse(dateLayout, f.text)
+		if err != nil {
+			return fmt.Errorf("availability: invalid date %q", f.text)
+		}
+		*f.dst = d
+	}
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*v = out
+	return nil
+}
+
+func checkVersion(v int) error {
+	if v < 0 || v > EncodingVersion {
+		return fmt.Errorf("availability: unsupported encoding version %d", v)
+	}
+	return nil
+}
+
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", errs.ErrInvalidTimezone, name)
+	}
+	return loc, nil
+}
@@ -0,0 +1,123 @@
+package availability
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeOfDayText(t *testing.T) {
+	data, err := json.Marshal(NewTimeOfDay(9, 5, 0))
+	if err != nil || string(data) != `"09:05"` {
+		t.Fatalf("unexpected encoding %s (%v)", data, err)
+	}
+	var tod TimeOfDay
+	if err := json.Unmarshal([]byte(`"24:00"`), &tod); err != nil || !tod.IsEndOfDay() {
+		t.Fatalf("expected end of day, got %v (%v)", tod, err)
+	}
+	if err := json.Unmarshal([]byte(`"25:00"`), &tod); err == nil {
+		t.Fatalf("expected invalid hour error")
+	}
+	if _, err := json.Marshal(TimeOfDay{Hour: 30}); err == nil {
+		t.Fatalf("expected marshal error for invalid time")
+	}
+}
+
+func TestWeeklyScheduleJSON(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("timezone DB unavailable")
+	}
+	ws, err := ParseSchedule("Mon-Fri 09:00-17:00; Sat 22:00-06:00; tz=Europe/Berlin")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	data, err := json.Marshal(ws)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	for _, want := range []string{`"version":1`, `"timezone":"Europe/Berlin"`, `"monday":[{"start":"09:00","end":"17:00"}]`, `"overnight":true`} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("expected %s in %s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "sunday") {
+		t.Fatalf("closed days should be omitted: %s", data)
+	}
+	var back WeeklySchedule
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if back.String() != ws.String() || back.Location.String() != berlin.String() {
+		t.Fatalf("round trip mismatch: %s vs %s", back, ws)
+	}
+
+	bad := []string{
+		`{"version":2,"timezone":"UTC","days":{}}`,
+		`{"timezone":"Mars/Olympus","days":{}}`,
+		`{"timezone":"UTC","days":{"funday":[]}}`,
+		`{"timezone":"UTC","days":{"monday":[{"start":"10:00","end":"09:00"}]}}`,
+	}
+	for _, in := range bad {
+		if err := json.Unmarshal([]byte(in), &back); err == nil {
+			t.Fatalf("expected error for %s", in)
+		}
+	}
+}
+
+func TestAvailabilityJSONRoundTrip(t *testing.T) {
+	data, err := os.ReadFile("../testdata/fixtures/availability.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	var a Availability
+	if err := json.Unmarshal(data, &a); err != nil {
+		t.Fatalf("unmarshal fixture: %v", err)
+	}
+	if a.Location.String() != "Europe/Berlin" || a.Bookings.Len() != 1 {
+		t.Fatalf("unexpected availability %+v", a)
+	}
+	if got := a.Weekly.GetDay(time.Saturday); len(got) != 1 || !got[0].End.IsEndOfDay() {
+		t.Fatalf("unexpected saturday %+v", got)
+	}
+	if len(a.Exceptions.Blocked) != 1 || len(a.Exceptions.Modified) != 1 {
+		t.Fatalf("unexpected exceptions %+v", a.Exceptions)
+	}
+
+	encoded, err := json.Marshal(a)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var back Availability
+	if err := json.Unmarshal(encoded, &back); err != nil {
+		t.Fatalf("unmarshal round trip: %v", err)
+	}
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, a.Location)
+	to := from.AddDate(0, 1, 0)
+	if got, want := back.GetSlots(from, to).Slots(), a.GetSlots(from, to).Slots(); len(got) != len(want) {
+		t.Fatalf("round trip changed availability: %d vs %d slots", len(got), len(want))
+	}
+
+	empty, err := json.Marshal(New(nil))
+	if err != nil || !strings.Contains(string(empty), `"bookings":[]`) || !strings.Contains(string(empty), `"timezone":"UTC"`) {
+		t.Fatalf("unexpected empty encoding %s (%v)", empty, err)
+	}
+	if err := json.Unmarshal([]byte(`{"version":1,"timezone":"UTC","weekly":{"timezone":"UTC","days":{}},"exceptions":{"blocked":[{"start":"2025-01-02T00:00:00Z","end":"2025-01-01T00:00:00Z"}]}}`), &back); err == nil {
+		t.Fatalf("expected invalid exception to be rejected")
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal(JSONSchema(), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	defs, _ := schema["$defs"].(map[string]any)
+	for _, name := range []string{"timeOfDay", "weeklySchedule", "exceptionSet", "timeSlot"} {
+		if _, ok := defs[name]; !ok {
+			t.Fatalf("schema missing definition %s", name)
+		}
+	}
+}
@@ -4,18 +4,19 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Melpic13/timeslot/internal/errs"
 	"github.com/Melpic13/timeslot/internal/timeutil"
 )
 
 // DateRange is an inclusive start, exclusive end date-time range.
 type DateRange struct {
-	Start time.Time
-	End   time.Time
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
 }
 
 func (r DateRange) Validate() error {
 	if !r.End.After(r.Start) {
-		return fmt.Errorf("availability: invalid date range")
+		return fmt.Errorf("%w: date range %s to %s", errs.ErrInvalidTimeRange, r.Start.Format(time.RFC3339), r.End.Format(time.RFC3339))
 	}
 	return nil
 }
@@ -26,22 +27,35 @@ func (r DateRange) Contains(t time.Time) bool {
 
 // DateOverride sets custom ranges for a specific date.
 type DateOverride struct {
-	Date   time.Time
-	Ranges []TimeRange
+	Date   time.Time   `json:"date"`
+	Ranges []TimeRange `json:"ranges"`
 }
 
-// ExceptionSet handles date-based exceptions.
+// ExceptionSet handles date-based exceptions. For each calendar date,
+// Availability.GetSlots applies them in this order:
+//
+//  1. Modified replaces the day's ranges; several overrides for the same
+//     date are merged. Without one, holidays and then the weekly schedule
+//     (or the schedule version in effect) provide the ranges.
+//  2. Extra hours are added on top of those ranges.
+//  3. Removed hours are taken out of the day.
+//  4. Blocked ranges are taken out.
+//  5. Available ranges are added back, so they win over everything above.
+//
+// Bookings are subtracted last.
 type ExceptionSet struct {
 	Blocked   []DateRange
 	Available []DateRange
 	Modified  []DateOverride
+	Extra     []DateOverride
+	Removed   []DateOverride
 }
 
 func (e ExceptionSet) AddBlockedDates(dates ...time.Time) ExceptionSet {
 	out := e
 	for _, d := range dates {
 		start := timeutil.StartOfDay(d, d.Location())
-		out.Blocked = append(out.Blocked, DateRange{Start: start, End: start.Add(24 * time.Hour)})
+		out.Blocked = append(out.Blocked, DateRange{Start: start, End: timeutil.AddDays(start, 1, d.Location())})
 	}
 	return out
 }
@@ -52,17 +66,54 @@ func (e ExceptionSet) AddBlockedRange(start, end time.Time) ExceptionSet {
 	return out
 }
 
+// AddAvailableRange opens [start, end) regardless of the schedule and of
+// blocked entries.
+func (e ExceptionSet) AddAvailableRange(start, end time.Time) ExceptionSet {
+	out := e
+	out.Available = append(append([]DateRange(nil), e.Available...), DateRange{Start: start, End: end})
+	return out
+}
+
+// AddExtraHours adds ranges on date on top of the regular hours.
+func (e ExceptionSet) AddExtraHours(date time.Time, ranges ...TimeRange) ExceptionSet {
+	out := e
+	out.Extra = append(append([]DateOverride(nil), e.Extra...), DateOverride{Date: date, Ranges: normalizeRanges(ranges)})
+	return out
+}
+
+// RemoveHours takes ranges out of date while keeping the rest of the day.
+func (e ExceptionSet) RemoveHours(date time.Time, ranges ...TimeRange) ExceptionSet {
+	out := e
+	out.Removed = append(append([]DateOverride(nil), e.Removed...), DateOverride{Date: date, Ranges: normalizeRanges(ranges)})
+	return out
+}
+
+// AddAvailableOverride replaces the ranges of date with ranges. Without
+// ranges it restores the regular hours of date instead: the overrides, extra
+// and removed hours recorded for it so far are dropped. Blocked and
+// Available entries are date-time ranges and are kept.
 func (e ExceptionSet) AddAvailableOverride(date time.Time, ranges ...TimeRange) ExceptionSet {
 	out := e
 	if len(ranges) == 0 {
-		start := timeutil.StartOfDay(date, date.Location())
-		out.Available = append(out.Available, DateRange{Start: start, End: start.Add(24 * time.Hour)})
+		out.Modified = withoutDate(e.Modified, date)
+		out.Extra = withoutDate(e.Extra, date)
+		out.Removed = withoutDate(e.Removed, date)
 		return out
 	}
 	out.Modified = append(out.Modified, DateOverride{Date: date, Ranges: normalizeRanges(ranges)})
 	return out
 }
 
+func withoutDate(overrides []DateOverride, date time.Time) []DateOverride {
+	var out []DateOverride
+	for _, o := range overrides {
+		if !timeutil.SameDay(o.Date, date, date.Location()) {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
 func (e ExceptionSet) IsBlocked(t time.Time) bool {
 	for _, r := range e.Blocked {
 		if r.Contains(t) {
@@ -81,13 +132,37 @@ func (e ExceptionSet) HasAvailableOverride(t time.Time) bool {
 	return false
 }
 
+// ModifiedForDate returns the replacement ranges for day, merging every
+// override recorded for that date.
 func (e ExceptionSet) ModifiedForDate(day time.Time) ([]TimeRange, bool) {
-	for _, m := range e.Modified {
+	return rangesForDate(e.Modified, day)
+}
+
+// ExtraForDate returns the merged extra hours for day.
+func (e ExceptionSet) ExtraForDate(day time.Time) []TimeRange {
+	ranges, _ := rangesForDate(e.Extra, day)
+	return ranges
+}
+
+// RemovedForDate returns the merged removed hours for day.
+func (e ExceptionSet) RemovedForDate(day time.Time) []TimeRange {
+	ranges, _ := rangesForDate(e.Removed, day)
+	return ranges
+}
+
+func rangesForDate(overrides []DateOverride, day time.Time) ([]TimeRange, bool) {
+	var ranges []TimeRange
+	found := false
+	for _, m := range overrides {
 		if timeutil.SameDay(m.Date, day, day.Location()) {
-			return append([]TimeRange(nil), m.Ranges...), true
+			ranges = append(ranges, m.Ranges...)
+			found = true
 		}
 	}
-	return nil, false
+	if len(ranges) > 1 {
+		ranges = normalizeRanges(ranges)
+	}
+	return ranges, found
 }
 
 func (e ExceptionSet) Validate() error {
@@ -101,10 +176,12 @@ func (e ExceptionSet) Validate() error {
 			return err
 		}
 	}
-	for _, m := range e.Modified {
-		for _, r := range m.Ranges {
-			if err := r.Validate(); err != nil {
-				return err
+	for _, overrides := range [][]DateOverride{e.Modified, e.Extra, e.Removed} {
+		for _, m := range overrides {
+			for _, r := range m.Ranges {
+				if err := r.Validate(); err != nil {
+					return err
+				}
 			}
 		}
 	}
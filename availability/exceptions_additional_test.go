@@ -10,7 +10,7 @@ func TestExceptionsRangeAndOverrides(t *testing.T) {
 	end := start.Add(24 * time.Hour)
 	es := ExceptionSet{}.
 		AddBlockedRange(start, end).
-		AddAvailableOverride(start)
+		AddAvailableRange(start, end)
 
 	if !es.IsBlocked(start.Add(time.Hour)) {
 		t.Fatalf("expected blocked")
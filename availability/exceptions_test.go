@@ -12,3 +12,75 @@ func TestExceptionSetBlockedDates(t *testing.T) {
 		t.Fatalf("expected blocked")
 	}
 }
+
+func TestModifiedForDateMergesOverrides(t *testing.T) {
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	es := ExceptionSet{}.
+		AddAvailableOverride(day, TimeRange{Start: NewTimeOfDay(13, 0, 0), End: NewTimeOfDay(15, 0, 0)}).
+		AddAvailableOverride(day, TimeRange{Start: NewTimeOfDay(9, 0, 0), End: NewTimeOfDay(11, 0, 0)}).
+		AddAvailableOverride(day, TimeRange{Start: NewTimeOfDay(10, 0, 0), End: NewTimeOfDay(12, 0, 0)})
+	ranges, ok := es.ModifiedForDate(day)
+	if !ok || len(ranges) != 2 {
+		t.Fatalf("expected two merged ranges, got %+v", ranges)
+	}
+	if ranges[0].Start.Hour != 9 || ranges[0].End.Hour != 12 || ranges[1].Start.Hour != 13 {
+		t.Fatalf("unexpected merged ranges %+v", ranges)
+	}
+}
+
+func TestExceptionPrecedence(t *testing.T) {
+	loc := time.UTC
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, loc) // Monday
+	at := func(h int) time.Time { return day.Add(time.Duration(h) * time.Hour) }
+	hours := func(from, to int) TimeRange {
+		return TimeRange{Start: NewTimeOfDay(from, 0, 0), End: NewTimeOfDay(to, 0, 0)}
+	}
+
+	a := New(loc)
+	a.Weekly = a.Weekly.SetDay(time.Monday, hours(9, 17))
+
+	tests := []struct {
+		name string
+		a    Availability
+		want []int // start/end hour pairs
+	}{
+		{"weekly", a, []int{9, 17}},
+		{"extra adds to weekly", a.AddExtraHours(day, hours(18, 20)), []int{9, 17, 18, 20}},
+		{"extra touching weekly merges", a.AddExtraHours(day, hours(7, 9)), []int{7, 17}},
+		{"removed keeps rest of day", a.RemoveHours(day, hours(12, 13)), []int{9, 12, 13, 17}},
+		{"modified replaces weekly", a.AddAvailableOverride(day, hours(10, 11)), []int{10, 11}},
+		{"extra adds to modified", a.AddAvailableOverride(day, hours(10, 11)).AddExtraHours(day, hours(14, 15)), []int{10, 11, 14, 15}},
+		{"removed beats extra", a.AddExtraHours(day, hours(18, 20)).RemoveHours(day, hours(19, 21)), []int{9, 17, 18, 19}},
+		{"blocked beats extra", a.AddExtraHours(day, hours(18, 20)).AddBlockedRange(at(16), at(19)), []int{9, 16, 19, 20}},
+		{"available beats blocked", a.AddBlockedDates(day).AddAvailableRange(at(10), at(11)), []int{10, 11}},
+		{"available beats removed", a.RemoveHours(day, hours(9, 17)).AddAvailableRange(at(12), at(13)), []int{12, 13}},
+		{"override without ranges restores regular hours", a.AddAvailableOverride(day, hours(10, 11)).AddExtraHours(day, hours(18, 20)).RemoveHours(day, hours(12, 13)).AddAvailableOverride(day), []int{9, 17}},
+		{"override without ranges keeps blocked", a.AddBlockedRange(at(9), at(12)).AddAvailableOverride(day), []int{12, 17}},
+	}
+	for _, tt := range tests {
+		got := tt.a.GetSlots(day, day.Add(24*time.Hour)).Slots()
+		if len(got)*2 != len(tt.want) {
+			t.Fatalf("%s: expected %d slots, got %v", tt.name, len(tt.want)/2, got)
+		}
+		for i, s := range got {
+			if !s.Start.Equal(at(tt.want[2*i])) || !s.End.Equal(at(tt.want[2*i+1])) {
+				t.Fatalf("%s: slot %d is %s, want %d-%d", tt.name, i, s, tt.want[2*i], tt.want[2*i+1])
+			}
+		}
+	}
+
+	tuesday := day.AddDate(0, 0, 1)
+	if got := a.AddAvailableOverride(tuesday).GetSlots(tuesday, tuesday.Add(24*time.Hour)); !got.IsEmpty() {
+		t.Fatalf("an override without ranges must not open a closed day, got %v", got.Slots())
+	}
+
+	other := a.AddExtraHours(day, hours(18, 20))
+	next := day.AddDate(0, 0, 7)
+	if got := other.GetSlots(next, next.Add(24*time.Hour)).TotalDuration(); got != 8*time.Hour {
+		t.Fatalf("extra hours should only apply to their date, got %v", got)
+	}
+	bad := ExceptionSet{Extra: []DateOverride{{Date: day, Ranges: []TimeRange{{Start: NewTimeOfDay(25, 0, 0), End: NewTimeOfDay(26, 0, 0)}}}}}
+	if err := bad.Validate(); err == nil {
+		t.Fatalf("expected invalid extra hours to fail validation")
+	}
+}
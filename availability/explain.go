@@ -0,0 +1,127 @@
+package availability
+
+import (
+	"time"
+
+	"github.com/Melpic13/timeslot/internal/timeutil"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// ReasonCode identifies why a time is or is not available.
+type ReasonCode string
+
+const (
+	// ReasonOutsideHours means part of the slot is outside the regular,
+	// modified or holiday hours of its day, including extra hours.
+	ReasonOutsideHours ReasonCode = "outside_hours"
+	// ReasonModifiedDay means the day's hours come from a date override.
+	ReasonModifiedDay ReasonCode = "modified_day"
+	// ReasonHoliday means the day is a holiday; Message holds its name.
+	ReasonHoliday ReasonCode = "holiday"
+	// ReasonExtraHours means extra hours recorded for the date cover the slot.
+	ReasonExtraHours ReasonCode = "extra_hours"
+	// ReasonRemovedHours means hours removed from the date overlap the slot.
+	ReasonRemovedHours ReasonCode = "removed_hours"
+	// ReasonBlocked means a blocked exception overlaps the slot.
+	ReasonBlocked ReasonCode = "blocked"
+	// ReasonAvailableOverride means an available exception reopens the slot.
+	ReasonAvailableOverride ReasonCode = "available_override"
+	// ReasonBooked means an existing booking overlaps the slot.
+	ReasonBooked ReasonCode = "booked"
+
+	// The remaining codes are reported by provider.Provider.Explain.
+	ReasonBuffer     ReasonCode = "buffer"
+	ReasonPast       ReasonCode = "past"
+	ReasonMinNotice  ReasonCode = "min_notice"
+	ReasonMaxAdvance ReasonCode = "max_advance"
+	// ReasonLimit means booking the slot would exceed a provider limit;
+	// Message names the limit.
+	ReasonLimit ReasonCode = "limit"
+	// ReasonStore means the booking store could not be read; Message holds
+	// the error.
+	ReasonStore ReasonCode = "store"
+)
+
+// Reason is one fact that contributed to an Explanation. Slot is the
+// exception, booking or hours involved, when there is one.
+type Reason struct {
+	Code    ReasonCode     `json:"code"`
+	Message string         `json:"message"`
+	Slot    *slot.TimeSlot `json:"slot,omitempty"`
+}
+
+// Explanation is the verdict for a slot together with the reasons behind
+// it, in the order the rules are applied.
+type Explanation struct {
+	Slot      slot.TimeSlot `json:"slot"`
+	Available bool          `json:"available"`
+	Reasons   []Reason      `json:"reasons,omitempty"`
+}
+
+// Has reports whether the explanation contains a reason with code.
+func (e Explanation) Has(code ReasonCode) bool {
+	for _, r := range e.Reasons {
+		if r.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Explain describes the availability of the instant t, probed the same way
+// as IsAvailable.
+func (a Availability) Explain(t time.Time) Explanation {
+	return a.ExplainSlot(slot.TimeSlot{Start: t, End: t.Add(time.Second), Location: a.locationOrUTC()})
+}
+
+// ExplainSlot reports whether all of s is available and why.
+func (a Availability) ExplainSlot(s slot.TimeSlot) Explanation {
+	loc := a.locationOrUTC()
+	out := Explanation{Slot: s}
+	if !s.End.After(s.Start) {
+		return out
+	}
+	window := slot.NewCollection(slot.TimeSlot{Start: s.Start.In(loc), End: s.End.In(loc), Location: loc})
+	add := func(code ReasonCode, message string, sl *slot.TimeSlot) {
+		out.Reasons = append(out.Reasons, Reason{Code: code, Message: message, Slot: sl})
+	}
+
+	holidays := a.holidayIndex()
+	for day := timeutil.StartOfDay(s.Start, loc); day.Before(s.End); day = timeutil.AddDays(day, 1, loc) {
+		if _, replaced := a.dayRanges(day, holidays); replaced != nil {
+			out.Reasons = append(out.Reasons, *replaced)
+		}
+	}
+
+	from, to := timeutil.AddDays(s.Start.In(loc), -1, loc), timeutil.AddDays(s.End.In(loc), 1, loc)
+	base, extra, removed := a.scheduled(from, to)
+	if covered(base.Union(extra).Merge(), window) < s.Duration() {
+		add(ReasonOutsideHours, "outside working hours", nil)
+	}
+	for _, x := range extra.Intersect(window).Slots() {
+		add(ReasonExtraHours, "extra hours", &x)
+	}
+	for _, x := range removed.Intersect(window).Slots() {
+		add(ReasonRemovedHours, "hours removed", &x)
+	}
+	for _, r := range a.Exceptions.Blocked {
+		if b := (slot.TimeSlot{Start: r.Start, End: r.End, Location: loc}); b.Overlaps(s) {
+			add(ReasonBlocked, "blocked by exception", &b)
+		}
+	}
+	for _, r := range a.Exceptions.Available {
+		if av := (slot.TimeSlot{Start: r.Start, End: r.End, Location: loc}); av.Overlaps(s) {
+			add(ReasonAvailableOverride, "opened by exception", &av)
+		}
+	}
+	for _, b := range a.Bookings.FindOverlaps(s) {
+		add(ReasonBooked, "existing booking", &b)
+	}
+
+	out.Available = covered(a.GetSlots(from, to), window) >= s.Duration()
+	return out
+}
+
+func covered(free, window slot.SlotCollection) time.Duration {
+	return free.Intersect(window).TotalDuration()
+}
@@ -0,0 +1,93 @@
+package availability
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/slot"
+)
+
+func TestExplainReasons(t *testing.T) {
+	loc := time.UTC
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, loc)
+	at := func(day time.Time, h int) time.Time { return day.Add(time.Duration(h) * time.Hour) }
+	tuesday := monday.AddDate(0, 0, 1)
+	wednesday := monday.AddDate(0, 0, 2)
+	thursday := monday.AddDate(0, 0, 3)
+
+	ws, err := ParseSchedule("Mon-Fri 09:00-17:00")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	a := New(loc)
+	a.Weekly = ws
+	a = a.AddBlockedRange(at(tuesday, 10), at(tuesday, 12)).
+		AddAvailableOverride(wednesday, TimeRange{Start: NewTimeOfDay(13, 0, 0), End: NewTimeOfDay(14, 0, 0)}).
+		AddExtraHours(thursday, TimeRange{Start: NewTimeOfDay(18, 0, 0), End: NewTimeOfDay(19, 0, 0)}).
+		AddBooking(slot.TimeSlot{Start: at(monday, 11), End: at(monday, 12), Location: loc})
+
+	tests := []struct {
+		name      string
+		at        time.Time
+		available bool
+		codes     []ReasonCode
+	}{
+		{"open", at(monday, 9), true, nil},
+		{"outside weekly hours", at(monday, 18), false, []ReasonCode{ReasonOutsideHours}},
+		{"booked", at(monday, 11), false, []ReasonCode{ReasonBooked}},
+		{"blocked", at(tuesday, 10), false, []ReasonCode{ReasonBlocked}},
+		{"modified day open", at(wednesday, 13), true, []ReasonCode{ReasonModifiedDay}},
+		{"modified day closed", at(wednesday, 9), false, []ReasonCode{ReasonModifiedDay, ReasonOutsideHours}},
+		{"extra hours", at(thursday, 18), true, []ReasonCode{ReasonExtraHours}},
+		{"weekend", at(monday.AddDate(0, 0, 5), 10), false, []ReasonCode{ReasonOutsideHours}},
+	}
+	for _, tt := range tests {
+		e := a.Explain(tt.at)
+		if e.Available != tt.available {
+			t.Fatalf("%s: expected available=%v, got %+v", tt.name, tt.available, e)
+		}
+		if e.Available != a.IsAvailable(tt.at) {
+			t.Fatalf("%s: explanation disagrees with IsAvailable", tt.name)
+		}
+		if len(e.Reasons) != len(tt.codes) {
+			t.Fatalf("%s: expected reasons %v, got %+v", tt.name, tt.codes, e.Reasons)
+		}
+		for i, code := range tt.codes {
+			if e.Reasons[i].Code != code || !e.Has(code) {
+				t.Fatalf("%s: reason %d is %s, want %s", tt.name, i, e.Reasons[i].Code, code)
+			}
+		}
+	}
+
+	blocked := a.Explain(at(tuesday, 11)).Reasons[0]
+	if blocked.Slot == nil || !blocked.Slot.Start.Equal(at(tuesday, 10)) {
+		t.Fatalf("blocked reason should carry the exception range, got %+v", blocked)
+	}
+}
+
+func TestExplainSlotPartialAndHoliday(t *testing.T) {
+	loc := time.UTC
+	a := New(loc)
+	a.Weekly = a.Weekly.SetDay(time.Monday, TimeRange{Start: NewTimeOfDay(9, 0, 0), End: NewTimeOfDay(17, 0, 0)})
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, loc)
+
+	partial := a.ExplainSlot(slot.TimeSlot{Start: monday.Add(16 * time.Hour), End: monday.Add(18 * time.Hour), Location: loc})
+	if partial.Available || !partial.Has(ReasonOutsideHours) {
+		t.Fatalf("slot running past closing should be unavailable, got %+v", partial)
+	}
+	if e := a.ExplainSlot(slot.TimeSlot{}); e.Available || len(e.Reasons) != 0 {
+		t.Fatalf("empty slot should explain nothing, got %+v", e)
+	}
+
+	holidays := NewHolidayCalendar("test", Holiday{Name: "Founders Day", Rule: FixedDate(time.January, 6)})
+	e := a.WithHolidays(holidays).Explain(monday.Add(10 * time.Hour))
+	if e.Available || !e.Has(ReasonHoliday) || e.Reasons[0].Message != "Founders Day" {
+		t.Fatalf("expected holiday reason, got %+v", e)
+	}
+	data, err := json.Marshal(e)
+	if err != nil || !strings.Contains(string(data), `"code":"holiday"`) || strings.Contains(string(data), `"slot":null`) {
+		t.Fatalf("unexpected explanation encoding %s (%v)", data, err)
+	}
+}
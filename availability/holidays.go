@@ -0,0 +1,309 @@
+package availability
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Melpic13/timeslot/internal/timeutil"
+)
+
+// HolidayRule computes the calendar date of a holiday in a given year. The
+// returned time is midnight UTC of that civil date; false means the holiday
+// does not occur that year.
+type HolidayRule interface {
+	Date(year int) (time.Time, bool)
+}
+
+type fixedDate struct {
+	month time.Month
+	day   int
+}
+
+// FixedDate is a holiday on the same month and day every year.
+func FixedDate(month time.Month, day int) HolidayRule {
+	return fixedDate{month: month, day: day}
+}
+
+func (r fixedDate) Date(year int) (time.Time, bool) {
+	d := time.Date(year, r.month, r.day, 0, 0, 0, 0, time.UTC)
+	return d, d.Month() == r.month
+}
+
+type nthWeekday struct {
+	month   time.Month
+	weekday time.Weekday
+	n       int
+}
+
+// NthWeekday is the nth weekday of a month, e.g. the 4th Thursday of
+// November. A negative n counts from the end, so -1 is the last one.
+func NthWeekday(month time.Month, weekday time.Weekday, n int) HolidayRule {
+	return nthWeekday{month: month, weekday: weekday, n: n}
+}
+
+func (r nthWeekday) Date(year int) (time.Time, bool) {
+	if r.n == 0 {
+		return time.Time{}, false
+	}
+	var d time.Time
+	if r.n > 0 {
+		first := time.Date(year, r.month, 1, 0, 0, 0, 0, time.UTC)
+		offset := (int(r.weekday) - int(first.Weekday()) + 7) % 7
+		d = first.AddDate(0, 0, offset+7*(r.n-1))
+	} else {
+		last := time.Date(year, r.month+1, 0, 0, 0, 0, 0, time.UTC)
+		offset := (int(last.Weekday()) - int(r.weekday) + 7) % 7
+		d = last.AddDate(0, 0, -offset+7*(r.n+1))
+	}
+	return d, d.Month() == r.month
+}
+
+type easterOffset struct {
+	days int
+}
+
+// EasterOffset is a holiday a fixed number of days from Western Easter
+// Sunday, e.g. -2 for Good Friday.
+func EasterOffset(days int) HolidayRule {
+	return easterOffset{days: days}
+}
+
+func (r easterOffset) Date(year int) (time.Time, bool) {
+	return Easter(year).AddDate(0, 0, r.days), true
+}
+
+// Easter returns Western (Gregorian) Easter Sunday of year at midnight UTC.
+func Easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// Observance moves a holiday that falls on a weekend to a working day.
+type Observance int
+
+const (
+	// ObservedAsIs keeps the holiday on its actual date.
+	ObservedAsIs Observance = iota
+	// ObservedMonday moves a Saturday or Sunday holiday to the next weekday
+	// that is not already a holiday, as UK bank holidays do.
+	ObservedMonday
+	// ObservedNearestWeekday moves Saturday holidays to Friday and Sunday
+	// holidays to Monday, as US federal holidays do.
+	ObservedNearestWeekday
+)
+
+// Holiday is a named rule. Hours, when set, replace the regular schedule on
+// the holiday instead of closing the whole day.
+type Holiday struct {
+	Name     string
+	Rule     HolidayRule
+	Observed Observance
+	Hours    []TimeRange
+}
+
+// HolidayDate is a holiday occurrence resolved to a calendar date.
+type HolidayDate struct {
+	Name  string
+	Date  time.Time
+	Hours []TimeRange
+}
+
+// HolidayCalendar is a named set of holidays.
+type HolidayCalendar struct {
+	Name     string
+	Holidays []Holiday
+}
+
+func NewHolidayCalendar(name string, holidays ...Holiday) HolidayCalendar {
+	return HolidayCalendar{Name: name, Holidays: append([]Holiday(nil), holidays...)}
+}
+
+func (c HolidayCalendar) Add(holidays ...Holiday) HolidayCalendar {
+	c.Holidays = append(append([]Holiday(nil), c.Holidays...), holidays...)
+	return c
+}
+
+// WithHours returns a copy in which every holiday uses ranges as override
+// hours instead of closing the day.
+func (c HolidayCalendar) WithHours(ranges ...TimeRange) HolidayCalendar {
+	out := make([]Holiday, len(c.Holidays))
+	for i, h := range c.Holidays {
+		h.Hours = normalizeRanges(ranges)
+		out[i] = h
+	}
+	c.Holidays = out
+	return c
+}
+
+func (c HolidayCalendar) IsZero() bool {
+	return len(c.Holidays) == 0
+}
+
+// Occurrences returns the observed holiday dates for year, at midnight in
+// loc and sorted by date. Observed dates may spill into a neighbouring year.
+func (c HolidayCalendar) Occurrences(year int, loc *time.Location) []HolidayDate {
+	if loc == nil {
+		loc = time.UTC
+	}
+	type occurrence struct {
+		holiday Holiday
+		date    time.Time
+	}
+	taken := map[int]bool{}
+	var fixed, shifted []occurrence
+	for _, h := range c.Holidays {
+		if h.Rule == nil {
+			continue
+		}
+		d, ok := h.Rule.Date(year)
+		if !ok {
+			continue
+		}
+		o := occurrence{holiday: h, date: d}
+		if h.Observed == ObservedAsIs || !isWeekend(d) {
+			taken[dateKey(d)] = true
+			fixed = append(fixed, o)
+			continue
+		}
+		shifted = append(shifted, o)
+	}
+	for i, o := range shifted {
+		switch o.holiday.Observed {
+		case ObservedMonday:
+			d := o.date
+			for isWeekend(d) || taken[dateKey(d)] {
+				d = d.AddDate(0, 0, 1)
+			}
+			shifted[i].date = d
+		case ObservedNearestWeekday:
+			if o.date.Weekday() == time.Saturday {
+				shifted[i].date = o.date.AddDate(0, 0, -1)
+			} else {
+				shifted[i].date = o.date.AddDate(0, 0, 1)
+			}
+		}
+		taken[dateKey(shifted[i].date)] = true
+	}
+	out := make([]HolidayDate, 0, len(fixed)+len(shifted))
+	for _, o := range append(fixed, shifted...) {
+		y, m, d := o.date.Date()
+		out = append(out, HolidayDate{
+			Name:  o.holiday.Name,
+			Date:  timeutil.Date(y, m, d, 0, 0, 0, 0, loc),
+			Hours: append([]TimeRange(nil), o.holiday.Hours...),
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out
+}
+
+// HolidayOn returns the holiday observed on the calendar date of day in
+// loc, if any.
+func (c HolidayCalendar) HolidayOn(day time.Time, loc *time.Location) (HolidayDate, bool) {
+	if loc == nil {
+		loc = day.Location()
+	}
+	return c.index(loc).on(day)
+}
+
+// holidayIndex caches the occurrences of a calendar per year, so looking up
+// every day of a range computes each year once.
+type holidayIndex struct {
+	cal   HolidayCalendar
+	loc   *time.Location
+	years map[int][]HolidayDate
+}
+
+func (c HolidayCalendar) index(loc *time.Location) *holidayIndex {
+	return &holidayIndex{cal: c, loc: loc, years: map[int][]HolidayDate{}}
+}
+
+// on returns the holiday observed on the calendar date of day. Observed
+// dates may move across a year boundary, so neighbouring years are checked.
+func (x *holidayIndex) on(day time.Time) (HolidayDate, bool) {
+	d := day.In(x.loc)
+	key := dateKey(d)
+	for y := d.Year() - 1; y <= d.Year()+1; y++ {
+		occurrences, ok := x.years[y]
+		if !ok {
+			occurrences = x.cal.Occurrences(y, x.loc)
+			x.years[y] = occurrences
+		}
+		for _, h := range occurrences {
+			if dateKey(h.Date) == key {
+				return h, true
+			}
+		}
+	}
+	return HolidayDate{}, false
+}
+
+func isWeekend(d time.Time) bool {
+	return d.Weekday() == time.Saturday || d.Weekday() == time.Sunday
+}
+
+// WithHolidays returns a copy that closes the days of cal, or opens them
+// only for the holiday's Hours. Per-date overrides still take precedence.
+func (a Availability) WithHolidays(cal HolidayCalendar) Availability {
+	a.Holidays = cal
+	return a
+}
+
+// USFederalHolidays returns the United States federal holidays.
+func USFederalHolidays() HolidayCalendar {
+	return NewHolidayCalendar("US",
+		Holiday{Name: "New Year's Day", Rule: FixedDate(time.January, 1), Observed: ObservedNearestWeekday},
+		Holiday{Name: "Martin Luther King Jr. Day", Rule: NthWeekday(time.January, time.Monday, 3)},
+		Holiday{Name: "Washington's Birthday", Rule: NthWeekday(time.February, time.Monday, 3)},
+		Holiday{Name: "Memorial Day", Rule: NthWeekday(time.May, time.Monday, -1)},
+		Holiday{Name: "Juneteenth", Rule: FixedDate(time.June, 19), Observed: ObservedNearestWeekday},
+		Holiday{Name: "Independence Day", Rule: FixedDate(time.July, 4), Observed: ObservedNearestWeekday},
+		Holiday{Name: "Labor Day", Rule: NthWeekday(time.September, time.Monday, 1)},
+		Holiday{Name: "Columbus Day", Rule: NthWeekday(time.October, time.Monday, 2)},
+		Holiday{Name: "Veterans Day", Rule: FixedDate(time.November, 11), Observed: ObservedNearestWeekday},
+		Holiday{Name: "Thanksgiving Day", Rule: NthWeekday(time.November, time.Thursday, 4)},
+		Holiday{Name: "Christmas Day", Rule: FixedDate(time.December, 25), Observed: ObservedNearestWeekday},
+	)
+}
+
+// UKBankHolidays returns the bank holidays of England and Wales.
+func UKBankHolidays() HolidayCalendar {
+	return NewHolidayCalendar("GB",
+		Holiday{Name: "New Year's Day", Rule: FixedDate(time.January, 1), Observed: ObservedMonday},
+		Holiday{Name: "Good Friday", Rule: EasterOffset(-2)},
+		Holiday{Name: "Easter Monday", Rule: EasterOffset(1)},
+		Holiday{Name: "Early May bank holiday", Rule: NthWeekday(time.May, time.Monday, 1)},
+		Holiday{Name: "Spring bank holiday", Rule: NthWeekday(time.May, time.Monday, -1)},
+		Holiday{Name: "Summer bank holiday", Rule: NthWeekday(time.August, time.Monday, -1)},
+		Holiday{Name: "Christmas Day", Rule: FixedDate(time.December, 25), Observed: ObservedMonday},
+		Holiday{Name: "Boxing Day", Rule: FixedDate(time.December, 26), Observed: ObservedMonday},
+	)
+}
+
+// GermanHolidays returns the nationwide public holidays of Germany.
+func GermanHolidays() HolidayCalendar {
+	return NewHolidayCalendar("DE",
+		Holiday{Name: "Neujahr", Rule: FixedDate(time.January, 1)},
+		Holiday{Name: "Karfreitag", Rule: EasterOffset(-2)},
+		Holiday{Name: "Ostermontag", Rule: EasterOffset(1)},
+		Holiday{Name: "Tag der Arbeit", Rule: FixedDate(time.May, 1)},
+		Holiday{Name: "Christi Himmelfahrt", Rule: EasterOffset(39)},
+		Holiday{Name: "Pfingstmontag", Rule: EasterOffset(50)},
+		Holiday{Name: "Tag der Deutschen Einheit", Rule: FixedDate(time.October, 3)},
+		Holiday{Name: "1. Weihnachtstag", Rule: FixedDate(time.December, 25)},
+		Holiday{Name: "2. Weihnachtstag", Rule: FixedDate(time.December, 26)},
+	)
+}
@@ -0,0 +1,141 @@
+package availability
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEaster(t *testing.T) {
+	tests := map[int]string{
+		2019: "2019-04-21",
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+		2038: "2038-04-25",
+	}
+	for year, want := range tests {
+		if got := Easter(year).Format("2006-01-02"); got != want {
+			t.Fatalf("easter %d: got %s want %s", year, got, want)
+		}
+	}
+}
+
+func TestHolidayRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule HolidayRule
+		year int
+		want string
+	}{
+		{"thanksgiving", NthWeekday(time.November, time.Thursday, 4), 2025, "2025-11-27"},
+		{"memorial day", NthWeekday(time.May, time.Monday, -1), 2025, "2025-05-26"},
+		{"labor day", NthWeekday(time.September, time.Monday, 1), 2025, "2025-09-01"},
+		{"good friday", EasterOffset(-2), 2025, "2025-04-18"},
+		{"fixed", FixedDate(time.July, 4), 2025, "2025-07-04"},
+	}
+	for _, tt := range tests {
+		got, ok := tt.rule.Date(tt.year)
+		if !ok || got.Format("2006-01-02") != tt.want {
+			t.Fatalf("%s: got %s (%v) want %s", tt.name, got.Format("2006-01-02"), ok, tt.want)
+		}
+	}
+	if _, ok := NthWeekday(time.February, time.Monday, 5).Date(2025); ok {
+		t.Fatalf("there is no fifth Monday in February 2025")
+	}
+	if _, ok := FixedDate(time.February, 29).Date(2025); ok {
+		t.Fatalf("Feb 29 should not occur in 2025")
+	}
+	if _, ok := NthWeekday(time.May, time.Monday, 0).Date(2025); ok {
+		t.Fatalf("n=0 should never occur")
+	}
+}
+
+func TestObservedShifting(t *testing.T) {
+	loc := time.UTC
+	us := USFederalHolidays()
+	// Jan 1 2022 was a Saturday, observed on Friday Dec 31 2021.
+	h, ok := us.HolidayOn(time.Date(2021, 12, 31, 12, 0, 0, 0, loc), loc)
+	if !ok || h.Name != "New Year's Day" {
+		t.Fatalf("expected observed New Year's Day, got %+v %v", h, ok)
+	}
+	if _, ok := us.HolidayOn(time.Date(2022, 1, 1, 0, 0, 0, 0, loc), loc); ok {
+		t.Fatalf("the actual Saturday should not be observed")
+	}
+
+	// Christmas 2021 fell on Saturday and Boxing Day on Sunday: Mon 27 and Tue 28.
+	uk := UKBankHolidays()
+	for day, name := range map[int]string{27: "Christmas Day", 28: "Boxing Day"} {
+		h, ok := uk.HolidayOn(time.Date(2021, 12, day, 0, 0, 0, 0, loc), loc)
+		if !ok || h.Name != name {
+			t.Fatalf("dec %d 2021: expected %s, got %+v", day, name, h)
+		}
+	}
+	// Christmas 2022 fell on Sunday: Boxing Day keeps Monday 26, Christmas moves to Tuesday 27.
+	if h, _ := uk.HolidayOn(time.Date(2022, 12, 27, 0, 0, 0, 0, loc), loc); h.Name != "Christmas Day" {
+		t.Fatalf("expected Christmas substitute on Dec 27 2022, got %+v", h)
+	}
+
+	if got := len(GermanHolidays().Occurrences(2025, loc)); got != 9 {
+		t.Fatalf("expected 9 German holidays, got %d", got)
+	}
+	occ := us.Occurrences(2025, loc)
+	for i := 1; i < len(occ); i++ {
+		if occ[i].Date.Before(occ[i-1].Date) {
+			t.Fatalf("occurrences should be sorted")
+		}
+	}
+}
+
+func TestAvailabilityWithHolidays(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("timezone DB unavailable")
+	}
+	ws, err := ParseSchedule("Mon-Fri 09:00-17:00; tz=Europe/Berlin")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	a := New(berlin)
+	a.Weekly = ws
+	a = a.WithHolidays(GermanHolidays())
+
+	easterMonday := time.Date(2025, 4, 21, 0, 0, 0, 0, berlin)
+	if got := a.GetSlots(easterMonday, easterMonday.AddDate(0, 0, 1)); !got.IsEmpty() {
+		t.Fatalf("Easter Monday should be closed, got %v", got.Slots())
+	}
+	if a.IsWorkingDay(easterMonday) {
+		t.Fatalf("holidays should not be working days")
+	}
+	if got := a.BusinessDaysBetween(time.Date(2025, 4, 17, 0, 0, 0, 0, berlin), time.Date(2025, 4, 22, 0, 0, 0, 0, berlin)); got != 1 {
+		t.Fatalf("expected only Tuesday after Easter to count, got %d", got)
+	}
+
+	short := a.WithHolidays(GermanHolidays().WithHours(TimeRange{Start: NewTimeOfDay(10, 0, 0), End: NewTimeOfDay(12, 0, 0)}))
+	if got := short.GetSlots(easterMonday, easterMonday.AddDate(0, 0, 1)); got.TotalDuration() != 2*time.Hour {
+		t.Fatalf("expected 2h of holiday hours, got %v", got.TotalDuration())
+	}
+
+	overridden := a.AddAvailableOverride(easterMonday, TimeRange{Start: NewTimeOfDay(8, 0, 0), End: NewTimeOfDay(9, 0, 0)})
+	if got := overridden.GetSlots(easterMonday, easterMonday.AddDate(0, 0, 1)); got.TotalDuration() != time.Hour {
+		t.Fatalf("per-date override should win over the holiday, got %v", got.TotalDuration())
+	}
+}
+
+func TestHolidayIndexComputesEachYearOnce(t *testing.T) {
+	x := USFederalHolidays().index(time.UTC)
+	found := 0
+	for d := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC); d.Year() == 2025; d = d.AddDate(0, 0, 1) {
+		h, ok := x.on(d)
+		if want, wantOK := USFederalHolidays().HolidayOn(d, time.UTC); ok != wantOK || h.Name != want.Name {
+			t.Fatalf("%s: index found %q, HolidayOn %q", d.Format("2006-01-02"), h.Name, want.Name)
+		}
+		if ok {
+			found++
+		}
+	}
+	if found != 11 {
+		t.Fatalf("expected 11 holidays in 2025, got %d", found)
+	}
+	if len(x.years) != 3 {
+		t.Fatalf("expected occurrences for 2024-2026 only, got %d years", len(x.years))
+	}
+}
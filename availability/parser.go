@@ -4,19 +4,39 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Melpic13/timeslot/internal/errs"
 )
 
+// ParseError reports a schedule syntax error at a 1-based column.
+type ParseError struct {
+	Input  string
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	msg := strings.TrimPrefix(e.Err.Error(), "availability: ")
+	return fmt.Sprintf("availability: column %d: %s", e.Column, msg)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
 // ParseTimeOfDay parses HH:MM or HH:MM:SS.
 func ParseTimeOfDay(input string) (TimeOfDay, error) {
 	parts := strings.Split(strings.TrimSpace(input), ":")
 	if len(parts) < 2 || len(parts) > 3 {
-		return TimeOfDay{}, fmt.Errorf("availability: invalid time of day %q", input)
+		return TimeOfDay{}, fmt.Errorf("%w: %q", errs.ErrInvalidTimeOfDay, input)
 	}
 	vals := make([]int, 3)
 	for i := 0; i < len(parts); i++ {
 		n, err := strconv.Atoi(parts[i])
 		if err != nil {
-			return TimeOfDay{}, fmt.Errorf("availability: invalid time component %q", parts[i])
+			return TimeOfDay{}, fmt.Errorf("%w: component %q", errs.ErrInvalidTimeOfDay, parts[i])
 		}
 		vals[i] = n
 	}
@@ -27,11 +47,17 @@ func ParseTimeOfDay(input string) (TimeOfDay, error) {
 	return tod, nil
 }
 
-// ParseTimeRange parses "HH:MM-HH:MM" or "HH:MM:SS-HH:MM:SS".
+// ParseTimeRange parses "HH:MM-HH:MM" or "HH:MM:SS-HH:MM:SS". An end at or
+// before the start, such as "22:00-06:00", yields an overnight range, and
+// "24:00" may be used as the end of the day. A "+1" suffix marks the end as
+// falling on the next day explicitly; the end must then be before the start.
 func ParseTimeRange(input string) (TimeRange, error) {
-	parts := strings.Split(strings.TrimSpace(input), "-")
+	trimmed := strings.TrimSpace(input)
+	nextDay := strings.HasSuffix(trimmed, "+1")
+	trimmed = strings.TrimSuffix(trimmed, "+1")
+	parts := strings.Split(trimmed, "-")
 	if len(parts) != 2 {
-		return TimeRange{}, fmt.Errorf("availability: invalid range %q", input)
+		return TimeRange{}, fmt.Errorf("%w: %q", errs.ErrInvalidTimeRange, input)
 	}
 	start, err := ParseTimeOfDay(parts[0])
 	if err != nil {
@@ -41,9 +67,134 @@ func ParseTimeRange(input string) (TimeRange, error) {
 	if err != nil {
 		return TimeRange{}, err
 	}
-	r := TimeRange{Start: start, End: end}
+	r := TimeRange{Start: start, End: end, Overnight: nextDay}
+	if !nextDay && !end.after(start) {
+		if end == start {
+			return TimeRange{}, fmt.Errorf("%w: empty range %q", errs.ErrInvalidTimeRange, input)
+		}
+		r.Overnight = true
+	}
 	if err := r.Validate(); err != nil {
 		return TimeRange{}, err
 	}
 	return r, nil
 }
+
+var weekdayNames = map[string]time.Weekday{
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+	"sun": time.Sunday, "sunday": time.Sunday,
+}
+
+// ParseSchedule parses the compact weekly schedule syntax produced by
+// WeeklySchedule.String, for example:
+//
+//	Mon-Fri 09:00-12:00,13:00-17:00; Sat 10:00-14:00; tz=Europe/Berlin
+//
+// Clauses are separated by ";". Each clause is either "tz=<IANA name>" or a
+// day list followed by comma-separated time ranges. Day lists accept single
+// days and ranges ("Mon,Wed-Fri", wrapping ranges such as "Fri-Mon" too).
+// Days named in several clauses get the union of their ranges. Errors are
+// returned as *ParseError.
+func ParseSchedule(input string) (WeeklySchedule, error) {
+	ranges := map[time.Weekday][]TimeRange{}
+	loc := time.UTC
+	tzSeen := false
+	offset := 0
+	for _, clause := range strings.Split(input, ";") {
+		start := offset
+		offset += len(clause) + 1
+		lead := len(clause) - len(strings.TrimLeftFunc(clause, unicode.IsSpace))
+		body := strings.TrimSpace(clause)
+		col := start + lead + 1
+		if body == "" {
+			continue
+		}
+		if name, ok := strings.CutPrefix(body, "tz="); ok {
+			if tzSeen {
+				return WeeklySchedule{}, &ParseError{Input: input, Column: col, Err: fmt.Errorf("availability: duplicate tz clause")}
+			}
+			l, err := time.LoadLocation(strings.TrimSpace(name))
+			if err != nil || strings.TrimSpace(name) == "" {
+				return WeeklySchedule{}, &ParseError{Input: input, Column: col + len("tz="), Err: fmt.Errorf("%w: %q", errs.ErrInvalidTimezone, name)}
+			}
+			loc = l
+			tzSeen = true
+			continue
+		}
+		split := strings.IndexFunc(body, unicode.IsSpace)
+		if split < 0 {
+			return WeeklySchedule{}, &ParseError{Input: input, Column: col + len(body), Err: fmt.Errorf("availability: expected time ranges after %q", body)}
+		}
+		days, err := parseDayList(body[:split])
+		if err != nil {
+			err.Input = input
+			err.Column += col - 1
+			return WeeklySchedule{}, err
+		}
+		rest := body[split:]
+		restCol := col + split + (len(rest) - len(strings.TrimLeftFunc(rest, unicode.IsSpace)))
+		parsed, err := parseRangeList(strings.TrimSpace(rest))
+		if err != nil {
+			err.Input = input
+			err.Column += restCol - 1
+			return WeeklySchedule{}, err
+		}
+		for _, d := range days {
+			ranges[d] = append(ranges[d], parsed...)
+		}
+	}
+	ws := NewWeeklySchedule(loc)
+	for d, rs := range ranges {
+		ws = ws.SetDay(d, rs...)
+	}
+	return ws, nil
+}
+
+func parseDayList(input string) ([]time.Weekday, *ParseError) {
+	var out []time.Weekday
+	pos := 0
+	for _, item := range strings.Split(input, ",") {
+		col := pos + 1
+		pos += len(item) + 1
+		from, to, isRange := strings.Cut(item, "-")
+		first, ok := weekdayNames[strings.ToLower(from)]
+		if !ok {
+			return nil, &ParseError{Column: col, Err: fmt.Errorf("availability: unknown weekday %q", from)}
+		}
+		if !isRange {
+			out = append(out, first)
+			continue
+		}
+		last, ok := weekdayNames[strings.ToLower(to)]
+		if !ok {
+			return nil, &ParseError{Column: col + len(from) + 1, Err: fmt.Errorf("availability: unknown weekday %q", to)}
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			out = append(out, d)
+			if d == last {
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func parseRangeList(input string) ([]TimeRange, *ParseError) {
+	var out []TimeRange
+	pos := 0
+	for _, item := range strings.Split(input, ",") {
+		col := pos + 1 + (len(item) - len(strings.TrimLeftFunc(item, unicode.IsSpace)))
+		pos += len(item) + 1
+		r, err := ParseTimeRange(item)
+		if err != nil {
+			return nil, &ParseError{Column: col, Err: err}
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
@@ -1,6 +1,13 @@
 package availability
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/internal/errs"
+)
 
 func TestParseTimeRange(t *testing.T) {
 	r, err := ParseTimeRange("09:00-17:00")
@@ -19,3 +26,136 @@ func FuzzParseTimeRange(f *testing.F) {
 		_, _ = ParseTimeRange(input)
 	})
 }
+
+func TestParseTimeRangeOvernightAndEndOfDay(t *testing.T) {
+	r, err := ParseTimeRange("22:00-06:00")
+	if err != nil || !r.Overnight {
+		t.Fatalf("expected overnight range: %+v %v", r, err)
+	}
+	r, err = ParseTimeRange("18:00-24:00")
+	if err != nil || r.Overnight || !r.End.IsEndOfDay() {
+		t.Fatalf("expected 24:00 end: %+v %v", r, err)
+	}
+	r, err = ParseTimeRange("22:00-06:00+1")
+	if err != nil || !r.Overnight || r.String() != "22:00-06:00" {
+		t.Fatalf("expected explicit overnight range: %+v %v", r, err)
+	}
+	if _, err := ParseTimeRange("09:00-17:00+1"); !errors.Is(err, errs.ErrInvalidTimeRange) {
+		t.Fatalf("a +1 range ending after its start spans more than a day, got %v", err)
+	}
+	if _, err := ParseTimeRange("09:00-09:00"); err == nil {
+		t.Fatalf("expected empty range error")
+	}
+	if _, err := ParseTimeRange("24:00-01:00"); err == nil {
+		t.Fatalf("expected error for range starting at 24:00")
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	ws, err := ParseSchedule("Mon-Fri 09:00-12:00,13:00-17:00; Sat 10:00-14:00; tz=Europe/Berlin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ws.Location.String() != "Europe/Berlin" {
+		t.Fatalf("unexpected location %s", ws.Location)
+	}
+	if got := ws.GetDay(time.Wednesday); len(got) != 2 || got[1].Start.Hour != 13 {
+		t.Fatalf("unexpected wednesday ranges %+v", got)
+	}
+	if got := ws.GetDay(time.Saturday); len(got) != 1 || got[0].End.Hour != 14 {
+		t.Fatalf("unexpected saturday ranges %+v", got)
+	}
+	if len(ws.GetDay(time.Sunday)) != 0 {
+		t.Fatalf("sunday should be closed")
+	}
+
+	wrap, err := ParseSchedule("  fri-MON 22:00-06:00 ; Wed,Friday 08:00-10:00")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, d := range []time.Weekday{time.Saturday, time.Sunday, time.Monday} {
+		if got := wrap.GetDay(d); len(got) != 1 || !got[0].Overnight {
+			t.Fatalf("%s: expected overnight range, got %+v", d, got)
+		}
+	}
+	if got := wrap.GetDay(time.Friday); len(got) != 2 {
+		t.Fatalf("friday should combine both clauses, got %+v", got)
+	}
+	if wrap.Location != time.UTC {
+		t.Fatalf("default location should be UTC")
+	}
+
+	empty, err := ParseSchedule("")
+	if err != nil || empty.String() != "" {
+		t.Fatalf("empty schedule should parse: %v", err)
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		column int
+	}{
+		{"Mon-Fri 09:00-12:00; Sat 10:00-1400", 26},
+		{"Mon-Fry 09:00-12:00", 5},
+		{"Mun 09:00-12:00", 1},
+		{"Mon,Tue,Xyz 09:00-12:00", 9},
+		{"Mon 09:00-12:00, 13:00-25:00", 18},
+		{"Mon", 4},
+		{"Mon 09:00-10:00; tz=Mars/Olympus", 21},
+		{"tz=UTC; tz=UTC", 9},
+	}
+	for _, tt := range tests {
+		_, err := ParseSchedule(tt.input)
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Fatalf("%q: expected *ParseError, got %v", tt.input, err)
+		}
+		if pe.Column != tt.column {
+			t.Fatalf("%q: expected column %d, got %d (%v)", tt.input, tt.column, pe.Column, err)
+		}
+		if !strings.Contains(err.Error(), "column") || pe.Unwrap() == nil {
+			t.Fatalf("%q: unexpected error text %q", tt.input, err)
+		}
+	}
+}
+
+func TestWeeklyScheduleStringRoundTrip(t *testing.T) {
+	inputs := []string{
+		"Mon-Fri 09:00-12:00,13:00-17:00; Sat 10:00-14:00; tz=Europe/Berlin",
+		"Mon,Wed-Fri 09:00-17:00; Tue 10:00-12:00",
+		"Sat-Sun 00:00-24:00",
+		"Mon 22:00-06:00; Tue 08:30:15-09:00",
+	}
+	for _, in := range inputs {
+		ws, err := ParseSchedule(in)
+		if err != nil {
+			t.Fatalf("%q: %v", in, err)
+		}
+		if got := ws.String(); got != in {
+			t.Fatalf("round trip mismatch:\n got %q\nwant %q", got, in)
+		}
+		again, err := ParseSchedule(ws.String())
+		if err != nil || again.String() != ws.String() {
+			t.Fatalf("%q: second round trip failed: %v", in, err)
+		}
+	}
+}
+
+func FuzzParseSchedule(f *testing.F) {
+	f.Add("Mon-Fri 09:00-12:00,13:00-17:00; Sat 10:00-14:00; tz=Europe/Berlin")
+	f.Add("Fri-Mon 22:00-06:00")
+	f.Fuzz(func(t *testing.T, input string) {
+		ws, err := ParseSchedule(input)
+		if err != nil {
+			return
+		}
+		again, err := ParseSchedule(ws.String())
+		if err != nil {
+			t.Fatalf("formatted schedule %q failed to parse: %v", ws.String(), err)
+		}
+		if again.String() != ws.String() {
+			t.Fatalf("round trip mismatch: %q vs %q", again.String(), ws.String())
+		}
+	})
+}
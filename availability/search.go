@@ -0,0 +1,105 @@
+package availability
+
+import (
+	"time"
+
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// DefaultSearchHorizon bounds NextAvailable and PreviousAvailable unless
+// WithSearchHorizon is given.
+const DefaultSearchHorizon = 366 * 24 * time.Hour
+
+type searchConfig struct {
+	horizon time.Duration
+}
+
+// SearchOption configures NextAvailable and PreviousAvailable.
+type SearchOption func(*searchConfig)
+
+// WithSearchHorizon limits how far from the starting point a search looks.
+// Non-positive values keep the default.
+func WithSearchHorizon(d time.Duration) SearchOption {
+	return func(c *searchConfig) {
+		if d > 0 {
+			c.horizon = d
+		}
+	}
+}
+
+func newSearchConfig(opts []SearchOption) searchConfig {
+	cfg := searchConfig{horizon: DefaultSearchHorizon}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// NextAvailable returns the first free window, after exceptions and
+// bookings, that lies at or after after and lasts at least minDuration. The
+// window is clipped to start no earlier than after and extends as far as the
+// free time does. The search walks a week at a time, so empty weeks cost a
+// single evaluation, and gives up at the search horizon.
+func (a Availability) NextAvailable(after time.Time, minDuration time.Duration, opts ...SearchOption) (slot.TimeSlot, bool) {
+	cfg := newSearchConfig(opts)
+	limit := after.Add(cfg.horizon)
+	var pending slot.TimeSlot
+	for cursor := after; cursor.Before(limit); {
+		next := cursor.Add(workingTimeChunk)
+		for _, s := range a.GetSlots(cursor, next).Slots() {
+			if !pending.IsZero() && s.Start.Equal(pending.End) {
+				pending.End = s.End
+				continue
+			}
+			if long(pending, minDuration) {
+				return pending, true
+			}
+			pending = s
+		}
+		// A window ending at the chunk boundary may continue in the next one.
+		if long(pending, minDuration) && pending.End.Before(next) {
+			return pending, true
+		}
+		cursor = next
+	}
+	if long(pending, minDuration) {
+		return pending, true
+	}
+	return slot.TimeSlot{}, false
+}
+
+// PreviousAvailable is the backwards counterpart of NextAvailable: it
+// returns the last free window of at least minDuration that ends at or
+// before before.
+func (a Availability) PreviousAvailable(before time.Time, minDuration time.Duration, opts ...SearchOption) (slot.TimeSlot, bool) {
+	cfg := newSearchConfig(opts)
+	limit := before.Add(-cfg.horizon)
+	var pending slot.TimeSlot
+	for cursor := before; cursor.After(limit); {
+		prev := cursor.Add(-workingTimeChunk)
+		free := a.GetSlots(prev, cursor).Slots()
+		for i := len(free) - 1; i >= 0; i-- {
+			s := free[i]
+			if !pending.IsZero() && s.End.Equal(pending.Start) {
+				pending.Start = s.Start
+				continue
+			}
+			if long(pending, minDuration) {
+				return pending, true
+			}
+			pending = s
+		}
+		if long(pending, minDuration) && pending.Start.After(prev) {
+			return pending, true
+		}
+		cursor = prev
+	}
+	if long(pending, minDuration) {
+		return pending, true
+	}
+	return slot.TimeSlot{}, false
+}
+
+func long(s slot.TimeSlot, minDuration time.Duration) bool {
+	return !s.IsZero() && s.Duration() > 0 && s.Duration() >= minDuration
+}
@@ -0,0 +1,86 @@
+package availability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/slot"
+)
+
+func TestAvailabilityNextAvailable(t *testing.T) {
+	loc := time.UTC
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, loc)
+	at := func(day time.Time, h int) time.Time { return day.Add(time.Duration(h) * time.Hour) }
+	a := New(loc)
+	a.Weekly = a.Weekly.SetDay(time.Monday, TimeRange{Start: NewTimeOfDay(9, 0, 0), End: NewTimeOfDay(17, 0, 0)})
+	a = a.AddBooking(slot.TimeSlot{Start: at(monday, 10), End: at(monday, 16), Location: loc})
+
+	got, ok := a.NextAvailable(at(monday, 8), 0)
+	if !ok || !got.Start.Equal(at(monday, 9)) || !got.End.Equal(at(monday, 10)) {
+		t.Fatalf("expected 09:00-10:00, got %s %v", got, ok)
+	}
+	got, ok = a.NextAvailable(at(monday, 9), 90*time.Minute)
+	if !ok || !got.Start.Equal(at(monday.AddDate(0, 0, 7), 9)) {
+		t.Fatalf("expected the following Monday for 90 minutes, got %s %v", got, ok)
+	}
+	got, ok = a.NextAvailable(at(monday, 9).Add(30*time.Minute), 0)
+	if !ok || !got.Start.Equal(at(monday, 9).Add(30*time.Minute)) {
+		t.Fatalf("window should be clipped to after, got %s", got)
+	}
+
+	// Block eight weeks of Mondays; the search skips the empty weeks.
+	blocked := a.AddBlockedRange(monday, monday.AddDate(0, 0, 56))
+	got, ok = blocked.NextAvailable(monday, time.Hour)
+	if !ok || !got.Start.Equal(at(monday.AddDate(0, 0, 56), 9)) {
+		t.Fatalf("expected first Monday after the block, got %s %v", got, ok)
+	}
+	if _, ok := blocked.NextAvailable(monday, time.Hour, WithSearchHorizon(30*24*time.Hour)); ok {
+		t.Fatalf("search should stop at the configured horizon")
+	}
+	if _, ok := New(loc).NextAvailable(monday, 0); ok {
+		t.Fatalf("empty availability should never be available")
+	}
+}
+
+func TestAvailabilityNextAvailableAcrossChunks(t *testing.T) {
+	loc := time.UTC
+	start := time.Date(2025, 1, 6, 12, 0, 0, 0, loc)
+	a := New(loc)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		a.Weekly = a.Weekly.SetDay(d, TimeRange{Start: NewTimeOfDay(0, 0, 0), End: EndOfDay})
+	}
+	// Always open: a 10-day window spans two search chunks and must stay whole.
+	got, ok := a.NextAvailable(start, 10*24*time.Hour, WithSearchHorizon(20*24*time.Hour))
+	if !ok || !got.Start.Equal(start) || got.Duration() < 10*24*time.Hour {
+		t.Fatalf("expected one long window from start, got %s %v", got, ok)
+	}
+	prev, ok := a.PreviousAvailable(start, 10*24*time.Hour, WithSearchHorizon(20*24*time.Hour))
+	if !ok || !prev.End.Equal(start) || prev.Duration() < 10*24*time.Hour {
+		t.Fatalf("expected one long window ending at start, got %s %v", prev, ok)
+	}
+}
+
+func TestAvailabilityPreviousAvailable(t *testing.T) {
+	loc := time.UTC
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, loc)
+	at := func(day time.Time, h int) time.Time { return day.Add(time.Duration(h) * time.Hour) }
+	a := New(loc)
+	a.Weekly = a.Weekly.SetDay(time.Monday, TimeRange{Start: NewTimeOfDay(9, 0, 0), End: NewTimeOfDay(17, 0, 0)})
+	a = a.AddBooking(slot.TimeSlot{Start: at(monday, 15), End: at(monday, 17), Location: loc})
+
+	got, ok := a.PreviousAvailable(at(monday, 20), 0)
+	if !ok || !got.Start.Equal(at(monday, 9)) || !got.End.Equal(at(monday, 15)) {
+		t.Fatalf("expected 09:00-15:00, got %s %v", got, ok)
+	}
+	got, ok = a.PreviousAvailable(at(monday, 10), 0)
+	if !ok || !got.End.Equal(at(monday, 10)) {
+		t.Fatalf("window should be clipped to before, got %s", got)
+	}
+	got, ok = a.PreviousAvailable(at(monday, 20), 7*time.Hour)
+	if !ok || !got.Start.Equal(at(monday.AddDate(0, 0, -7), 9)) {
+		t.Fatalf("expected the previous Monday, got %s %v", got, ok)
+	}
+	if _, ok := a.PreviousAvailable(at(monday, 20), 9*time.Hour); ok {
+		t.Fatalf("no window is long enough")
+	}
+}
@@ -0,0 +1,101 @@
+package availability
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Melpic13/timeslot/internal/errs"
+)
+
+// ScheduleVersion is a weekly schedule that replaces Availability.Weekly
+// between two calendar dates, both inclusive. A zero From or Until leaves
+// that side open. Dates are read in the location they were created in.
+type ScheduleVersion struct {
+	Weekly WeeklySchedule
+	From   time.Time
+	Until  time.Time
+}
+
+func (v ScheduleVersion) Validate() error {
+	if err := v.Weekly.Validate(); err != nil {
+		return err
+	}
+	if from, until := v.bounds(); until < from {
+		return fmt.Errorf("%w: schedule version ends before it starts", errs.ErrInvalidTimeRange)
+	}
+	return nil
+}
+
+// Covers reports whether the calendar date of day in loc falls inside the
+// version.
+func (v ScheduleVersion) Covers(day time.Time, loc *time.Location) bool {
+	if loc != nil {
+		day = day.In(loc)
+	}
+	key := dateKey(day)
+	from, until := v.bounds()
+	return key >= from && key <= until
+}
+
+func (v ScheduleVersion) bounds() (int, int) {
+	from, until := math.MinInt, math.MaxInt
+	if !v.From.IsZero() {
+		from = dateKey(v.From)
+	}
+	if !v.Until.IsZero() {
+		until = dateKey(v.Until)
+	}
+	return from, until
+}
+
+func (v ScheduleVersion) overlaps(other ScheduleVersion) bool {
+	f1, u1 := v.bounds()
+	f2, u2 := other.bounds()
+	return f1 <= u2 && f2 <= u1
+}
+
+func dateKey(t time.Time) int {
+	y, m, d := t.Date()
+	return y*10000 + int(m)*100 + d
+}
+
+// AddScheduleVersion returns a copy that uses ws on the dates from..until,
+// inclusive. Use a zero time for an open-ended side.
+func (a Availability) AddScheduleVersion(ws WeeklySchedule, from, until time.Time) Availability {
+	versions := append(append([]ScheduleVersion(nil), a.Versions...), ScheduleVersion{Weekly: ws, From: from, Until: until})
+	sort.SliceStable(versions, func(i, j int) bool {
+		fi, _ := versions[i].bounds()
+		fj, _ := versions[j].bounds()
+		return fi < fj
+	})
+	a.Versions = versions
+	return a
+}
+
+// ScheduleFor returns the weekly schedule in effect on the calendar date of
+// day, falling back to Weekly when no version covers it.
+func (a Availability) ScheduleFor(day time.Time) WeeklySchedule {
+	loc := a.locationOrUTC()
+	for _, v := range a.Versions {
+		if v.Covers(day, loc) {
+			return v.Weekly
+		}
+	}
+	return a.Weekly
+}
+
+func validateVersions(versions []ScheduleVersion) error {
+	for i, v := range versions {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+		for _, other := range versions[i+1:] {
+			if v.overlaps(other) {
+				return fmt.Errorf("%w: overlapping schedule versions", errs.ErrSlotOverlap)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package availability
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduleVersionsPickSchedulePerDay(t *testing.T) {
+	loc := time.UTC
+	regular := NewWeeklySchedule(loc).SetDay(time.Monday, TimeRange{Start: NewTimeOfDay(9, 0, 0), End: NewTimeOfDay(17, 0, 0)})
+	summer := NewWeeklySchedule(loc).SetDay(time.Monday, TimeRange{Start: NewTimeOfDay(7, 0, 0), End: NewTimeOfDay(13, 0, 0)})
+	a := New(loc)
+	a.Weekly = regular
+	a = a.AddScheduleVersion(summer, time.Date(2025, 6, 1, 0, 0, 0, 0, loc), time.Date(2025, 8, 31, 0, 0, 0, 0, loc))
+	if err := a.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	tests := []struct {
+		day   time.Time
+		start int
+	}{
+		{time.Date(2025, 5, 26, 0, 0, 0, 0, loc), 9},
+		{time.Date(2025, 6, 2, 0, 0, 0, 0, loc), 7},
+		{time.Date(2025, 9, 1, 0, 0, 0, 0, loc), 9},
+	}
+	for _, tt := range tests {
+		slots := a.GetSlots(tt.day, tt.day.Add(24*time.Hour)).Slots()
+		if len(slots) != 1 || slots[0].Start.Hour() != tt.start {
+			t.Fatalf("%s: expected start at %d, got %v", tt.day.Format("2006-01-02"), tt.start, slots)
+		}
+	}
+
+	// Until is inclusive: Sunday Aug 31 is covered, the following Monday is not.
+	if !a.Versions[0].Covers(time.Date(2025, 8, 31, 23, 0, 0, 0, loc), loc) {
+		t.Fatalf("expected until date to be inclusive")
+	}
+	if got := a.ScheduleFor(time.Date(2025, 7, 7, 12, 0, 0, 0, loc)); len(got.Monday) != 1 || got.Monday[0].Start.Hour != 7 {
+		t.Fatalf("expected summer schedule, got %+v", got)
+	}
+}
+
+func TestScheduleVersionsOpenEndedAndValidation(t *testing.T) {
+	loc := time.UTC
+	later := NewWeeklySchedule(loc).SetDay(time.Tuesday, TimeRange{Start: NewTimeOfDay(10, 0, 0), End: NewTimeOfDay(11, 0, 0)})
+	a := New(loc).AddScheduleVersion(later, time.Date(2025, 3, 1, 0, 0, 0, 0, loc), time.Time{})
+	if got := a.GetSlots(time.Date(2030, 1, 1, 0, 0, 0, 0, loc), time.Date(2030, 1, 2, 0, 0, 0, 0, loc)); got.Len() != 1 {
+		t.Fatalf("open-ended version should apply far in the future, got %d slots", got.Len())
+	}
+	if got := a.GetSlots(time.Date(2025, 2, 25, 0, 0, 0, 0, loc), time.Date(2025, 2, 26, 0, 0, 0, 0, loc)); got.Len() != 0 {
+		t.Fatalf("version should not apply before its start")
+	}
+
+	overlapping := a.AddScheduleVersion(later, time.Time{}, time.Date(2025, 3, 1, 0, 0, 0, 0, loc))
+	if err := overlapping.Validate(); err == nil || !strings.Contains(err.Error(), "overlapping") {
+		t.Fatalf("expected overlap error, got %v", err)
+	}
+	if !overlapping.Versions[0].From.IsZero() {
+		t.Fatalf("versions should be sorted by start")
+	}
+	if len(a.Versions) != 1 {
+		t.Fatalf("AddScheduleVersion should not mutate the receiver")
+	}
+	reversed := New(loc).AddScheduleVersion(later, time.Date(2025, 3, 2, 0, 0, 0, 0, loc), time.Date(2025, 3, 1, 0, 0, 0, 0, loc))
+	if err := reversed.Validate(); err == nil {
+		t.Fatalf("expected error for version ending before it starts")
+	}
+	adjacent := a.AddScheduleVersion(later, time.Time{}, time.Date(2025, 2, 28, 0, 0, 0, 0, loc))
+	if err := adjacent.Validate(); err != nil {
+		t.Fatalf("adjacent versions should be valid: %v", err)
+	}
+}
+
+func TestScheduleVersionsJSON(t *testing.T) {
+	loc := time.UTC
+	summer := NewWeeklySchedule(loc).SetDay(time.Monday, TimeRange{Start: NewTimeOfDay(7, 0, 0), End: NewTimeOfDay(13, 0, 0)})
+	a := New(loc).AddScheduleVersion(summer, time.Date(2025, 6, 1, 0, 0, 0, 0, loc), time.Time{})
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"from":"2025-06-01"`) || strings.Contains(string(data), `"until"`) {
+		t.Fatalf("unexpected version encoding %s", data)
+	}
+	var back Availability
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(back.Versions) != 1 || !back.Versions[0].Until.IsZero() || back.Versions[0].From.Month() != time.June {
+		t.Fatalf("unexpected versions %+v", back.Versions)
+	}
+	if err := json.Unmarshal([]byte(`{"timezone":"UTC","weekly":{"timezone":"UTC","days":{}},"versions":[{"from":"June","weekly":{"timezone":"UTC","days":{}}}]}`), &back); err == nil {
+		t.Fatalf("expected invalid date error")
+	}
+}
@@ -3,8 +3,10 @@ package availability
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/Melpic13/timeslot/internal/errs"
 	"github.com/Melpic13/timeslot/internal/timeutil"
 	"github.com/Melpic13/timeslot/slot"
 )
@@ -16,23 +18,45 @@ type TimeOfDay struct {
 	Second int
 }
 
+// EndOfDay is 24:00, the midnight that closes a day. It is only valid as
+// the end of a TimeRange.
+var EndOfDay = TimeOfDay{Hour: 24}
+
+const secondsPerDay = 24 * 60 * 60
+
 func NewTimeOfDay(hour, minute, second int) TimeOfDay {
 	return TimeOfDay{Hour: hour, Minute: minute, Second: second}
 }
 
 func (t TimeOfDay) Validate() error {
+	if t.IsEndOfDay() {
+		return nil
+	}
 	if t.Hour < 0 || t.Hour > 23 {
-		return fmt.Errorf("availability: invalid hour %d", t.Hour)
+		return fmt.Errorf("%w: hour %d", errs.ErrInvalidTimeOfDay, t.Hour)
 	}
 	if t.Minute < 0 || t.Minute > 59 {
-		return fmt.Errorf("availability: invalid minute %d", t.Minute)
+		return fmt.Errorf("%w: minute %d", errs.ErrInvalidTimeOfDay, t.Minute)
 	}
 	if t.Second < 0 || t.Second > 59 {
-		return fmt.Errorf("availability: invalid second %d", t.Second)
+		return fmt.Errorf("%w: second %d", errs.ErrInvalidTimeOfDay, t.Second)
 	}
 	return nil
 }
 
+// String formats t as HH:MM, or HH:MM:SS when seconds are set.
+func (t TimeOfDay) String() string {
+	if t.Second != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+	}
+	return fmt.Sprintf("%02d:%02d", t.Hour, t.Minute)
+}
+
+// IsEndOfDay reports whether t is 24:00.
+func (t TimeOfDay) IsEndOfDay() bool {
+	return t == EndOfDay
+}
+
 func (t TimeOfDay) ToTime(day time.Time, loc *time.Location) time.Time {
 	l := loc
 	if l == nil {
@@ -40,13 +64,15 @@ func (t TimeOfDay) ToTime(day time.Time, loc *time.Location) time.Time {
 	}
 	d := day.In(l)
 	y, m, dd := d.Date()
-	return time.Date(y, m, dd, t.Hour, t.Minute, t.Second, 0, l)
+	return timeutil.Date(y, m, dd, t.Hour, t.Minute, t.Second, 0, l)
 }
 
-// TimeRange represents a time window within a day (no date).
+// TimeRange represents a time window within a day (no date). Overnight
+// ranges end on the following day, e.g. 22:00-06:00 for a night shift.
 type TimeRange struct {
-	Start TimeOfDay
-	End   TimeOfDay
+	Start     TimeOfDay `json:"start"`
+	End       TimeOfDay `json:"end"`
+	Overnight bool      `json:"overnight,omitempty"`
 }
 
 func (r TimeRange) Validate() error {
@@ -56,12 +82,72 @@ func (r TimeRange) Validate() error {
 	if err := r.End.Validate(); err != nil {
 		return err
 	}
+	if r.Start.IsEndOfDay() {
+		return fmt.Errorf("%w: range cannot start at 24:00", errs.ErrInvalidTimeRange)
+	}
+	if r.Overnight {
+		if r.End.IsEndOfDay() {
+			return fmt.Errorf("%w: overnight range cannot end at 24:00", errs.ErrInvalidTimeRange)
+		}
+		if !r.Start.after(r.End) {
+			return fmt.Errorf("%w: overnight range %v-%v must end before it starts", errs.ErrInvalidTimeRange, r.Start, r.End)
+		}
+		return nil
+	}
 	if !r.End.after(r.Start) {
-		return fmt.Errorf("availability: invalid range %v-%v", r.Start, r.End)
+		return fmt.Errorf("%w: %v-%v", errs.ErrInvalidTimeRange, r.Start, r.End)
 	}
 	return nil
 }
 
+// String formats the range as accepted by ParseTimeRange.
+func (r TimeRange) String() string {
+	return r.Start.String() + "-" + r.End.String()
+}
+
+// SlotOn returns the concrete slot covered by the range on the calendar
+// date of day in loc.
+func (r TimeRange) SlotOn(day time.Time, loc *time.Location) slot.TimeSlot {
+	if loc == nil {
+		loc = day.Location()
+	}
+	start := r.Start.ToTime(day, loc)
+	endDay := day
+	if r.Overnight {
+		endDay = timeutil.AddDays(day, 1, loc)
+	}
+	return slot.TimeSlot{Start: start, End: r.End.ToTime(endDay, loc), Location: loc}
+}
+
+// span returns the range as seconds from the start of its day; overnight
+// ends are past secondsPerDay.
+func (r TimeRange) span() (int, int) {
+	end := r.End.seconds()
+	if r.Overnight {
+		end += secondsPerDay
+	}
+	return r.Start.seconds(), end
+}
+
+func rangeFromSpan(start, end int) TimeRange {
+	r := TimeRange{Start: timeOfDayFromSeconds(start)}
+	if end > secondsPerDay {
+		r.End = timeOfDayFromSeconds(end - secondsPerDay)
+		r.Overnight = true
+		return r
+	}
+	r.End = timeOfDayFromSeconds(end)
+	return r
+}
+
+func (t TimeOfDay) seconds() int {
+	return t.Hour*3600 + t.Minute*60 + t.Second
+}
+
+func timeOfDayFromSeconds(n int) TimeOfDay {
+	return TimeOfDay{Hour: n / 3600, Minute: n % 3600 / 60, Second: n % 60}
+}
+
 func (t TimeOfDay) after(other TimeOfDay) bool {
 	if t.Hour != other.Hour {
 		return t.Hour > other.Hour
@@ -145,19 +231,19 @@ func (w WeeklySchedule) GenerateSlots(from, to time.Time) slot.SlotCollection {
 		return slot.NewCollection()
 	}
 	var out []slot.TimeSlot
-	for d := startDay; !d.After(endDay); d = d.Add(24 * time.Hour) {
+	// Start a day early so overnight ranges spilling into from are included.
+	for d := timeutil.AddDays(startDay, -1, loc); !d.After(endDay); d = timeutil.AddDays(d, 1, loc) {
 		ranges := w.GetDay(d.In(loc).Weekday())
 		for _, r := range ranges {
-			rs := r.Start.ToTime(d, loc)
-			re := r.End.ToTime(d, loc)
-			if !re.After(rs) {
+			rs := r.SlotOn(d, loc)
+			if !rs.End.After(rs.Start) {
 				continue
 			}
-			s := rs
+			s := rs.Start
 			if s.Before(from) {
 				s = from
 			}
-			e := re
+			e := rs.End
 			if e.After(to) {
 				e = to
 			}
@@ -170,36 +256,22 @@ func (w WeeklySchedule) GenerateSlots(from, to time.Time) slot.SlotCollection {
 }
 
 func (w WeeklySchedule) IsAvailable(t time.Time) bool {
-	loc := w.locationOrUTC()
-	t = t.In(loc)
-	for _, r := range w.GetDay(t.Weekday()) {
-		start := r.Start.ToTime(t, loc)
-		end := r.End.ToTime(t, loc)
-		if (t.Equal(start) || t.After(start)) && t.Before(end) {
-			return true
-		}
-	}
-	return false
+	probe := slot.TimeSlot{Start: t, End: t.Add(time.Second), Location: w.locationOrUTC()}
+	return len(w.GenerateSlots(t, t.Add(time.Second)).FindOverlaps(probe)) > 0
 }
 
+// NextAvailable returns the first available instant at or after after. The
+// schedule repeats every week, so if nothing opens within a week (plus a
+// day for overnight ranges) nothing ever will. Use
+// Availability.NextAvailable to account for exceptions and bookings.
 func (w WeeklySchedule) NextAvailable(after time.Time) (time.Time, bool) {
 	loc := w.locationOrUTC()
-	probe := after.In(loc)
-	for i := 0; i < 14; i++ {
-		day := timeutil.StartOfDay(probe, loc).Add(time.Duration(i) * 24 * time.Hour)
-		ranges := w.GetDay(day.Weekday())
-		for _, r := range ranges {
-			start := r.Start.ToTime(day, loc)
-			if start.After(after) {
-				return start, true
-			}
-			end := r.End.ToTime(day, loc)
-			if (after.Equal(start) || after.After(start)) && after.Before(end) {
-				return after, true
-			}
-		}
+	horizon := timeutil.AddDays(after, 8, loc)
+	first, ok := w.GenerateSlots(after, horizon).First()
+	if !ok {
+		return time.Time{}, false
 	}
-	return time.Time{}, false
+	return first.Start, true
 }
 
 func (w WeeklySchedule) MergeWith(other WeeklySchedule) WeeklySchedule {
@@ -231,6 +303,57 @@ func (w WeeklySchedule) Validate() error {
 	return nil
 }
 
+// String formats the schedule in the syntax accepted by ParseSchedule. Days
+// sharing the same ranges are grouped into one clause.
+func (w WeeklySchedule) String() string {
+	week := []time.Weekday{
+		time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+		time.Friday, time.Saturday, time.Sunday,
+	}
+	var keys []string
+	groups := map[string][]int{}
+	for i, d := range week {
+		ranges := w.GetDay(d)
+		if len(ranges) == 0 {
+			continue
+		}
+		parts := make([]string, 0, len(ranges))
+		for _, r := range ranges {
+			parts = append(parts, r.String())
+		}
+		key := strings.Join(parts, ",")
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+	clauses := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		clauses = append(clauses, formatDayList(week, groups[key])+" "+key)
+	}
+	if loc := w.locationOrUTC(); loc.String() != time.UTC.String() {
+		clauses = append(clauses, "tz="+loc.String())
+	}
+	return strings.Join(clauses, "; ")
+}
+
+func formatDayList(week []time.Weekday, idx []int) string {
+	var parts []string
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && idx[j+1] == idx[j]+1 {
+			j++
+		}
+		name := week[idx[i]].String()[:3]
+		if j > i {
+			name += "-" + week[idx[j]].String()[:3]
+		}
+		parts = append(parts, name)
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
 func (w WeeklySchedule) locationOrUTC() *time.Location {
 	if w.Location == nil {
 		return time.UTC
@@ -246,31 +369,25 @@ func normalizeRanges(ranges []TimeRange) []TimeRange {
 		}
 	}
 	sort.Slice(valid, func(i, j int) bool {
-		a := valid[i]
-		b := valid[j]
-		if a.Start.Hour != b.Start.Hour {
-			return a.Start.Hour < b.Start.Hour
-		}
-		if a.Start.Minute != b.Start.Minute {
-			return a.Start.Minute < b.Start.Minute
-		}
-		return a.Start.Second < b.Start.Second
+		return valid[i].Start.seconds() < valid[j].Start.seconds()
 	})
 	if len(valid) == 0 {
 		return nil
 	}
-	out := []TimeRange{valid[0]}
+	curStart, curEnd := valid[0].span()
+	out := make([]TimeRange, 0, len(valid))
 	for i := 1; i < len(valid); i++ {
-		curr := out[len(out)-1]
-		next := valid[i]
-		if !next.Start.after(curr.End) {
-			if next.End.after(curr.End) {
-				curr.End = next.End
-				out[len(out)-1] = curr
+		start, end := valid[i].span()
+		// Merging must not stretch a range to a full day or more, which no
+		// overnight range can express.
+		if start <= curEnd && max(end, curEnd)-curStart < secondsPerDay {
+			if end > curEnd {
+				curEnd = end
 			}
 			continue
 		}
-		out = append(out, next)
+		out = append(out, rangeFromSpan(curStart, curEnd))
+		curStart, curEnd = start, end
 	}
-	return out
+	return append(out, rangeFromSpan(curStart, curEnd))
 }
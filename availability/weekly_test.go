@@ -1,8 +1,11 @@
 package availability
 
 import (
+	"errors"
 	"testing"
 	"time"
+
+	"github.com/Melpic13/timeslot/internal/errs"
 )
 
 func TestWeeklyGenerateSlots(t *testing.T) {
@@ -22,3 +25,106 @@ func TestWeeklyIsAvailable(t *testing.T) {
 		t.Fatalf("expected available")
 	}
 }
+
+func TestWeeklyOvernightRanges(t *testing.T) {
+	night := TimeRange{Start: NewTimeOfDay(22, 0, 0), End: NewTimeOfDay(6, 0, 0), Overnight: true}
+	if err := night.Validate(); err != nil {
+		t.Fatalf("overnight range should be valid: %v", err)
+	}
+	ws := NewWeeklySchedule(time.UTC).
+		SetDay(time.Monday, night).
+		SetDay(time.Tuesday, night)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) // Monday
+	to := from.AddDate(0, 0, 3)
+	slots := ws.GenerateSlots(from, to)
+	if slots.Len() != 2 || slots.TotalDuration() != 16*time.Hour {
+		t.Fatalf("expected two 8h night shifts, got %d (%v)", slots.Len(), slots.TotalDuration())
+	}
+	first, _ := slots.First()
+	if !first.Start.Equal(time.Date(2024, 1, 1, 22, 0, 0, 0, time.UTC)) || !first.End.Equal(time.Date(2024, 1, 2, 6, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected overnight slot %s", first)
+	}
+
+	if !ws.IsAvailable(time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected availability after midnight from the previous day's range")
+	}
+	if ws.IsAvailable(time.Date(2024, 1, 2, 6, 0, 0, 0, time.UTC)) {
+		t.Fatalf("overnight range end should be exclusive")
+	}
+	if next, ok := ws.NextAvailable(time.Date(2024, 1, 2, 4, 0, 0, 0, time.UTC)); !ok || next.Hour() != 4 {
+		t.Fatalf("expected in-range next available, got %v %v", next, ok)
+	}
+
+	// Starting the window after midnight must still pick up Monday's shift.
+	spill := ws.GenerateSlots(time.Date(2024, 1, 2, 1, 0, 0, 0, time.UTC), time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC))
+	if spill.TotalDuration() != 5*time.Hour {
+		t.Fatalf("expected 5h spill, got %v", spill.TotalDuration())
+	}
+}
+
+func TestWeeklyEndOfDayIsContiguous(t *testing.T) {
+	ws := NewWeeklySchedule(time.UTC)
+	for _, d := range []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday} {
+		ws = ws.SetDay(d, TimeRange{Start: NewTimeOfDay(0, 0, 0), End: EndOfDay})
+	}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	slots := ws.GenerateSlots(from, from.AddDate(0, 0, 7))
+	if slots.Len() != 1 || slots.TotalDuration() != 7*24*time.Hour {
+		t.Fatalf("24/7 schedule should be one contiguous slot, got %d (%v)", slots.Len(), slots.TotalDuration())
+	}
+	if !ws.IsAvailable(time.Date(2024, 1, 1, 23, 59, 59, 500, time.UTC)) {
+		t.Fatalf("expected no hole before midnight")
+	}
+}
+
+func TestOvernightValidationAndNormalize(t *testing.T) {
+	if err := (TimeRange{Start: EndOfDay, End: NewTimeOfDay(1, 0, 0), Overnight: true}).Validate(); err == nil {
+		t.Fatalf("range starting at 24:00 should be invalid")
+	}
+	if err := (TimeRange{Start: NewTimeOfDay(22, 0, 0), End: EndOfDay, Overnight: true}).Validate(); err == nil {
+		t.Fatalf("overnight range ending at 24:00 should be invalid")
+	}
+	for _, end := range []TimeOfDay{NewTimeOfDay(22, 0, 0), NewTimeOfDay(23, 0, 0)} {
+		r := TimeRange{Start: NewTimeOfDay(22, 0, 0), End: end, Overnight: true}
+		if err := r.Validate(); !errors.Is(err, errs.ErrInvalidTimeRange) {
+			t.Fatalf("overnight %v should span less than a day, got %v", r, err)
+		}
+	}
+	if err := (TimeOfDay{Hour: 24, Minute: 1}).Validate(); err == nil {
+		t.Fatalf("only 24:00:00 is a valid end of day")
+	}
+
+	merged := normalizeRanges([]TimeRange{
+		{Start: NewTimeOfDay(18, 0, 0), End: EndOfDay},
+		{Start: NewTimeOfDay(22, 0, 0), End: NewTimeOfDay(2, 0, 0), Overnight: true},
+		{Start: NewTimeOfDay(8, 0, 0), End: NewTimeOfDay(12, 0, 0)},
+	})
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 ranges, got %d", len(merged))
+	}
+	if got := merged[1]; got.Start.Hour != 18 || got.End.Hour != 2 || !got.Overnight {
+		t.Fatalf("expected 18:00-02:00 overnight, got %+v", got)
+	}
+
+	long := normalizeRanges([]TimeRange{
+		{Start: NewTimeOfDay(1, 0, 0), End: EndOfDay},
+		{Start: NewTimeOfDay(22, 0, 0), End: NewTimeOfDay(5, 0, 0), Overnight: true},
+	})
+	for _, r := range long {
+		if err := r.Validate(); err != nil {
+			t.Fatalf("normalized ranges must stay valid: %+v %v", long, err)
+		}
+	}
+	if len(long) != 2 {
+		t.Fatalf("ranges spanning a day should not merge, got %+v", long)
+	}
+
+	toMidnight := normalizeRanges([]TimeRange{
+		{Start: NewTimeOfDay(18, 0, 0), End: NewTimeOfDay(22, 0, 0)},
+		{Start: NewTimeOfDay(21, 0, 0), End: EndOfDay},
+	})
+	if len(toMidnight) != 1 || !toMidnight[0].End.IsEndOfDay() || toMidnight[0].Overnight {
+		t.Fatalf("expected 18:00-24:00, got %+v", toMidnight)
+	}
+}
@@ -0,0 +1,72 @@
+// Package clock abstracts the current time so that time-sensitive APIs in
+// provider, query and conflict can run against fixed instants in tests.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock reports the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// Real returns the system clock.
+func Real() Clock {
+	return realClock{}
+}
+
+// Or returns c, or the system clock when c is nil.
+func Or(c Clock) Clock {
+	if c == nil {
+		return realClock{}
+	}
+	return c
+}
+
+// Func adapts a plain function to Clock.
+type Func func() time.Time
+
+func (f Func) Now() time.Time { return f() }
+
+// Fixed returns a clock that always reports t.
+func Fixed(t time.Time) Clock {
+	return Func(func() time.Time { return t })
+}
+
+// Manual is a clock that only moves when told to. It is safe for
+// concurrent use.
+type Manual struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewManual(t time.Time) *Manual {
+	return &Manual{now: t}
+}
+
+func (m *Manual) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// Set moves the clock to t, which may be in the past.
+func (m *Manual) Set(t time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = t
+}
+
+// Advance moves the clock forward by d and returns the new time.
+func (m *Manual) Advance(d time.Duration) time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = m.now.Add(d)
+	return m.now
+}
@@ -0,0 +1,50 @@
+package clock
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRealAndOr(t *testing.T) {
+	before := time.Now()
+	got := Real().Now()
+	if got.Before(before) || got.After(time.Now()) {
+		t.Fatalf("real clock out of range: %v", got)
+	}
+	if _, ok := Or(nil).(realClock); !ok {
+		t.Fatalf("Or(nil) should fall back to the real clock")
+	}
+	fixed := Fixed(time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC))
+	if Or(fixed) == nil || !Or(fixed).Now().Equal(fixed.Now()) {
+		t.Fatalf("Or should keep a non-nil clock")
+	}
+}
+
+func TestManual(t *testing.T) {
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	m := NewManual(start)
+	if !m.Now().Equal(start) {
+		t.Fatalf("unexpected start %v", m.Now())
+	}
+	if got := m.Advance(time.Hour); !got.Equal(start.Add(time.Hour)) || !m.Now().Equal(got) {
+		t.Fatalf("advance got %v", got)
+	}
+	m.Set(start.Add(-time.Hour))
+	if !m.Now().Equal(start.Add(-time.Hour)) {
+		t.Fatalf("set should allow moving backwards")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.Advance(time.Minute)
+		}()
+	}
+	wg.Wait()
+	if got := m.Now(); !got.Equal(start.Add(-time.Hour + 10*time.Minute)) {
+		t.Fatalf("concurrent advances lost updates: %v", got)
+	}
+}
@@ -3,6 +3,8 @@ package conflict
 import (
 	"time"
 
+	"github.com/Melpic13/timeslot/clock"
+	"github.com/Melpic13/timeslot/internal/errs"
 	"github.com/Melpic13/timeslot/provider"
 	"github.com/Melpic13/timeslot/query"
 	"github.com/Melpic13/timeslot/slot"
@@ -18,6 +20,11 @@ type DetectorOptions struct {
 	IncludeBuffers     bool
 	TravelTimeFunc     func(from, to *provider.Provider) time.Duration
 	AllowDoubleBooking bool
+	// EnforceBookingWindow reports slots in the past or outside a
+	// provider's MinNotice/MaxAdvance window as ConflictBookingWindow.
+	EnforceBookingWindow bool
+	// Clock overrides each provider's clock for booking-window checks.
+	Clock clock.Clock
 }
 
 type Conflict struct {
@@ -25,6 +32,18 @@ type Conflict struct {
 	Slot       slot.TimeSlot
 	Providers  []*provider.Provider
 	Resolution []ResolutionOption
+	// Cause is the booking error behind the conflict, such as
+	// provider.ErrConflict or provider.ErrInsufficientNotice.
+	Cause error
+}
+
+// Err returns the conflict as a *timeslot.SlotError wrapping Cause.
+func (c Conflict) Err() error {
+	cause := c.Cause
+	if cause == nil {
+		cause = errs.ErrConflict
+	}
+	return errs.Wrap("check", c.Slot, cause)
 }
 
 type ConflictType int
@@ -34,6 +53,7 @@ const (
 	ConflictBuffer
 	ConflictTravelTime
 	ConflictDoubleBooking
+	ConflictBookingWindow
 )
 
 func NewDetector(providers ...*provider.Provider) *ConflictDetector {
@@ -45,24 +65,52 @@ func (d *ConflictDetector) AddProvider(p *provider.Provider) *ConflictDetector {
 	return d
 }
 
+// WithOptions replaces the detector options.
+func (d *ConflictDetector) WithOptions(opts DetectorOptions) *ConflictDetector {
+	d.options = opts
+	return d
+}
+
+// WithClock sets the clock used for booking-window checks.
+func (d *ConflictDetector) WithClock(c clock.Clock) *ConflictDetector {
+	d.options.Clock = c
+	return d
+}
+
+func (d *ConflictDetector) Options() DetectorOptions {
+	return d.options
+}
+
+func (d *ConflictDetector) now(p *provider.Provider) time.Time {
+	if d.options.Clock != nil {
+		return d.options.Clock.Now()
+	}
+	return p.Now()
+}
+
 func (d *ConflictDetector) Check(s slot.TimeSlot, p *provider.Provider) []Conflict {
 	var out []Conflict
 	if p == nil {
 		return out
 	}
+	if d.options.EnforceBookingWindow {
+		if err := p.CheckBookingWindow(s, d.now(p)); err != nil {
+			out = append(out, Conflict{Type: ConflictBookingWindow, Slot: s, Providers: []*provider.Provider{p}, Resolution: defaultResolutionOptions(s), Cause: err})
+		}
+	}
 	if !p.IsAvailable(s) {
-		out = append(out, Conflict{Type: ConflictOverlap, Slot: s, Providers: []*provider.Provider{p}, Resolution: defaultResolutionOptions(s)})
+		out = append(out, Conflict{Type: ConflictOverlap, Slot: s, Providers: []*provider.Provider{p}, Resolution: defaultResolutionOptions(s), Cause: provider.ErrConflict})
 	}
 	if d.options.IncludeBuffers {
 		expanded := p.EffectiveAvailability(s)
 		if !expanded.Equal(s) && !p.IsAvailable(expanded) {
-			out = append(out, Conflict{Type: ConflictBuffer, Slot: expanded, Providers: []*provider.Provider{p}, Resolution: defaultResolutionOptions(expanded)})
+			out = append(out, Conflict{Type: ConflictBuffer, Slot: expanded, Providers: []*provider.Provider{p}, Resolution: defaultResolutionOptions(expanded), Cause: provider.ErrConflict})
 		}
 	}
 	if !d.options.AllowDoubleBooking {
 		for _, existing := range p.GetBookings(slotWindowAround(s, 24*time.Hour).Start, slotWindowAround(s, 24*time.Hour).End) {
 			if existing.Overlaps(s) {
-				out = append(out, Conflict{Type: ConflictDoubleBooking, Slot: s, Providers: []*provider.Provider{p}, Resolution: defaultResolutionOptions(s)})
+				out = append(out, Conflict{Type: ConflictDoubleBooking, Slot: s, Providers: []*provider.Provider{p}, Resolution: defaultResolutionOptions(s), Cause: provider.ErrConflict})
 				break
 			}
 		}
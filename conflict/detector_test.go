@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Melpic13/timeslot/availability"
+	"github.com/Melpic13/timeslot/clock"
 	"github.com/Melpic13/timeslot/provider"
 	"github.com/Melpic13/timeslot/query"
 	"github.com/Melpic13/timeslot/slot"
@@ -32,3 +33,29 @@ func TestResolve(t *testing.T) {
 		t.Fatalf("expected resolved slot")
 	}
 }
+
+func TestDetectorBookingWindowWithClock(t *testing.T) {
+	now := time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+	ws := availability.NewWeeklySchedule(time.UTC).SetDay(time.Monday, availability.TimeRange{Start: availability.NewTimeOfDay(9, 0, 0), End: availability.NewTimeOfDay(17, 0, 0)})
+	p := provider.NewProvider("a", provider.WithWeeklySchedule(ws), provider.WithMinNotice(2*time.Hour), provider.WithClock(clock.Fixed(now)))
+	s := slot.TimeSlot{Start: now.Add(3 * time.Hour), End: now.Add(4 * time.Hour), Location: time.UTC}
+
+	d := NewDetector(p)
+	if got := d.Check(s, p); len(got) != 0 {
+		t.Fatalf("booking window should not be checked by default, got %+v", got)
+	}
+	d.WithOptions(DetectorOptions{EnforceBookingWindow: true})
+	if got := d.Check(s, p); len(got) != 0 {
+		t.Fatalf("slot is bookable at the provider's time, got %+v", got)
+	}
+	manual := clock.NewManual(now)
+	d.WithClock(manual)
+	manual.Advance(2 * time.Hour)
+	got := d.Check(s, p)
+	if len(got) != 1 || got[0].Type != ConflictBookingWindow {
+		t.Fatalf("expected booking window conflict after advancing the detector clock, got %+v", got)
+	}
+	if d.Options().Clock != manual || !d.Options().EnforceBookingWindow {
+		t.Fatalf("unexpected options %+v", d.Options())
+	}
+}
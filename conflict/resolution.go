@@ -1,12 +1,17 @@
 package conflict
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/Melpic13/timeslot/internal/errs"
 	"github.com/Melpic13/timeslot/slot"
 )
 
+// ErrUnknownStrategy is returned by Resolve for an unsupported strategy.
+var ErrUnknownStrategy = errors.New("conflict: unknown resolution strategy")
+
 type ResolutionStrategy int
 
 const (
@@ -33,7 +38,7 @@ func resolveSlot(c Conflict, strategy ResolutionStrategy) (*slot.TimeSlot, error
 	case StrategySkip:
 		return nil, nil
 	default:
-		return nil, fmt.Errorf("unknown resolution strategy")
+		return nil, errs.Wrap("resolve", s, fmt.Errorf("%w %d", ErrUnknownStrategy, strategy))
 	}
 }
 
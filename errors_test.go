@@ -6,6 +6,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Melpic13/timeslot/availability"
+	"github.com/Melpic13/timeslot/clock"
+	"github.com/Melpic13/timeslot/conflict"
+	"github.com/Melpic13/timeslot/provider"
+	"github.com/Melpic13/timeslot/query"
 	"github.com/Melpic13/timeslot/slot"
 )
 
@@ -35,3 +40,72 @@ func TestSlotErrorFormattingAndUnwrap(t *testing.T) {
 		t.Fatalf("nil unwrap should be nil")
 	}
 }
+
+func TestPackageErrorsMatchRootSentinels(t *testing.T) {
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	morning := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+	ws := availability.NewWeeklySchedule(time.UTC).SetDay(time.Monday, availability.TimeRange{Start: availability.NewTimeOfDay(9, 0, 0), End: availability.NewTimeOfDay(12, 0, 0)})
+	p := NewProvider("p", provider.WithWeeklySchedule(ws), provider.WithClock(clock.Fixed(monday)), provider.WithMaxAdvance(48*time.Hour))
+	booked, err := p.Book(morning)
+	if err != nil {
+		t.Fatalf("book: %v", err)
+	}
+	late := NewProvider("p", provider.WithClock(clock.Fixed(monday.Add(12*time.Hour))))
+	conflicts := conflict.NewDetector().Check(morning, booked)
+	if len(conflicts) == 0 {
+		t.Fatalf("expected a conflict")
+	}
+	_, resolveErr := conflict.NewDetector().Resolve(conflicts[0], conflict.ResolutionStrategy(99))
+	_, parseErr := availability.ParseSchedule("Mon 09:00-12:00; tz=Nowhere/City")
+	_, slotErr := NewSlot(monday, monday)
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"book past", errOf(late.Book(morning)), ErrPastTime},
+		{"book too far", errOf(p.Book(morning.Shift(7 * 24 * time.Hour))), ErrTooFarAdvance},
+		{"book outside hours", errOf(p.Book(morning.Shift(3 * time.Hour))), ErrNoAvailability},
+		{"book taken", errOf(booked.Book(morning)), ErrConflict},
+		{"conflict", conflicts[0].Err(), ErrConflict},
+		{"resolve", resolveErr, conflict.ErrUnknownStrategy},
+		{"query duration", query.NewQuery().Between(monday, monday.Add(time.Hour)).Validate(), ErrInvalidDuration},
+		{"query range", query.NewQuery().Duration(time.Hour).Between(monday, monday).Validate(), ErrInvalidTimeRange},
+		{"query time of day", query.TimeOfDay{Hour: 25}.Validate(), ErrInvalidTimeOfDay},
+		{"time of day", availability.NewTimeOfDay(9, 60, 0).Validate(), ErrInvalidTimeOfDay},
+		{"time range", availability.TimeRange{Start: availability.NewTimeOfDay(12, 0, 0), End: availability.NewTimeOfDay(9, 0, 0)}.Validate(), ErrInvalidTimeRange},
+		{"parse timezone", parseErr, ErrInvalidTimezone},
+		{"new slot", slotErr, ErrInvalidTimeRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestSlotErrorsCarryContext(t *testing.T) {
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	p := NewProvider("p", provider.WithClock(clock.Fixed(monday.Add(12*time.Hour))))
+	past := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+
+	_, err := p.Book(past)
+	var se *SlotError
+	if !errors.As(err, &se) || se.Op != "book" || !se.Slot.Equal(past) || !errors.Is(se.Err, ErrPastTime) {
+		t.Fatalf("expected a book SlotError for %v, got %#v", past, err)
+	}
+	_, err = p.CancelBooking(past)
+	if !errors.As(err, &se) || se.Op != "cancel" || !errors.Is(err, provider.ErrBookingNotFound) {
+		t.Fatalf("expected a cancel SlotError, got %v", err)
+	}
+	if !errors.As(query.NewQuery().Duration(time.Hour).Between(monday, monday).Validate(), &se) || se.Op != "query" {
+		t.Fatalf("expected a query SlotError")
+	}
+}
+
+func errOf[T any](_ T, err error) error {
+	return err
+}
@@ -0,0 +1,61 @@
+// Package errs holds the error values shared by every timeslot package. The
+// root package re-exports them, so callers match against timeslot.ErrX.
+package errs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// ErrInvalidTimeRange is the slot package's error, so that slot validation
+// failures surfacing through other packages match it too.
+var ErrInvalidTimeRange = slot.ErrInvalidTimeRange
+
+var (
+	ErrInvalidDuration    = errors.New("timeslot: duration must be positive")
+	ErrInvalidTimeOfDay   = errors.New("timeslot: invalid time of day")
+	ErrSlotOverlap        = errors.New("timeslot: slots overlap")
+	ErrNoAvailability     = errors.New("timeslot: no availability found")
+	ErrConflict           = errors.New("timeslot: booking conflict detected")
+	ErrInvalidTimezone    = errors.New("timeslot: invalid timezone")
+	ErrPastTime           = errors.New("timeslot: cannot book in the past")
+	ErrInsufficientNotice = errors.New("timeslot: insufficient booking notice")
+	ErrTooFarAdvance      = errors.New("timeslot: booking too far in advance")
+	ErrLimitExceeded      = errors.New("timeslot: booking limit exceeded")
+	ErrInvalidRecurrence  = errors.New("timeslot: invalid recurrence rule")
+	ErrInvalidICS         = errors.New("timeslot: invalid iCal format")
+)
+
+// SlotError wraps a slot-specific failure with operation context.
+type SlotError struct {
+	Op   string
+	Slot slot.TimeSlot
+	Err  error
+}
+
+// Wrap returns err as a *SlotError for op on s, or nil when err is nil.
+func Wrap(op string, s slot.TimeSlot, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &SlotError{Op: op, Slot: s, Err: err}
+}
+
+func (e *SlotError) Error() string {
+	if e == nil {
+		return "timeslot: <nil>"
+	}
+	if e.Err == nil {
+		return fmt.Sprintf("timeslot: %s failed for %s", e.Op, e.Slot.String())
+	}
+	return fmt.Sprintf("timeslot: %s failed for %s: %v", e.Op, e.Slot.String(), e.Err)
+}
+
+func (e *SlotError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
@@ -0,0 +1,24 @@
+package errs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/slot"
+)
+
+func TestWrap(t *testing.T) {
+	if Wrap("book", slot.TimeSlot{}, nil) != nil {
+		t.Fatalf("wrapping nil should return nil")
+	}
+	s := slot.TimeSlot{Start: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), Location: time.UTC}
+	err := Wrap("book", s, ErrConflict)
+	var se *SlotError
+	if !errors.As(err, &se) || se.Op != "book" || !errors.Is(err, ErrConflict) {
+		t.Fatalf("unexpected wrapped error %v", err)
+	}
+	if !errors.Is(ErrInvalidTimeRange, slot.ErrInvalidTimeRange) {
+		t.Fatalf("expected the slot range error to be shared")
+	}
+}
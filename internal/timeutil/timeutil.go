@@ -9,11 +9,85 @@ func StartOfDay(t time.Time, loc *time.Location) time.Time {
 	}
 	t = t.In(l)
 	y, m, d := t.Date()
-	return time.Date(y, m, d, 0, 0, 0, 0, l)
+	return Date(y, m, d, 0, 0, 0, 0, l)
 }
 
 func EndOfDay(t time.Time, loc *time.Location) time.Time {
-	return StartOfDay(t, loc).Add(24*time.Hour - time.Nanosecond)
+	return AddDays(t, 1, loc).Add(-time.Nanosecond)
+}
+
+// AddDays returns the start of the calendar day n days after t's date in
+// loc. Unlike adding multiples of 24 hours it is unaffected by DST.
+func AddDays(t time.Time, n int, loc *time.Location) time.Time {
+	l := loc
+	if l == nil {
+		l = t.Location()
+	}
+	y, m, d := t.In(l).Date()
+	return Date(y, m, d+n, 0, 0, 0, 0, l)
+}
+
+// Date is time.Date with deterministic handling of DST transitions: a wall
+// clock time skipped by a spring-forward gap resolves to the instant the
+// gap ends, and a time repeated by a fall-back overlap resolves to its
+// first occurrence.
+func Date(year int, month time.Month, day, hour, min, sec, nsec int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, min, sec, nsec, loc)
+	want := time.Date(year, month, day, hour, min, sec, nsec, time.UTC)
+	got := wallClock(t)
+	switch {
+	case got.After(want):
+		start, _ := t.ZoneBounds()
+		return start
+	case got.Before(want):
+		_, end := t.ZoneBounds()
+		return end
+	}
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return t
+	}
+	_, prevOffset := start.Add(-time.Nanosecond).Zone()
+	_, offset := t.Zone()
+	if prevOffset <= offset {
+		return t
+	}
+	earlier := t.Add(-time.Duration(prevOffset-offset) * time.Second)
+	if earlier.Before(start) && wallClock(earlier).Equal(want) {
+		return earlier
+	}
+	return t
+}
+
+// IsNonexistent reports whether the wall clock time is skipped in loc.
+func IsNonexistent(year int, month time.Month, day, hour, min, sec int, loc *time.Location) bool {
+	t := time.Date(year, month, day, hour, min, sec, 0, loc)
+	return !wallClock(t).Equal(time.Date(year, month, day, hour, min, sec, 0, time.UTC))
+}
+
+// IsAmbiguous reports whether the wall clock time occurs twice in loc.
+func IsAmbiguous(year int, month time.Month, day, hour, min, sec int, loc *time.Location) bool {
+	if IsNonexistent(year, month, day, hour, min, sec, loc) {
+		return false
+	}
+	t := time.Date(year, month, day, hour, min, sec, 0, loc)
+	_, offset := t.Zone()
+	for _, probe := range []time.Time{t.Add(-3 * time.Hour), t.Add(3 * time.Hour)} {
+		_, other := probe.Zone()
+		if other == offset {
+			continue
+		}
+		alt := t.Add(time.Duration(offset-other) * time.Second)
+		if _, altOffset := alt.Zone(); altOffset == other && wallClock(alt).Equal(wallClock(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+func wallClock(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
 }
 
 func SameDay(a, b time.Time, loc *time.Location) bool {
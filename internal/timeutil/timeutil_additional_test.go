@@ -30,3 +30,46 @@ func TestEndOfDayClampAndOverlap(t *testing.T) {
 		t.Fatalf("expected no overlap at boundary")
 	}
 }
+
+func TestDateResolvesDSTTransitions(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("timezone DB unavailable")
+	}
+	gap := Date(2024, 3, 10, 2, 30, 0, 0, ny)
+	if want := time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC); !gap.Equal(want) {
+		t.Fatalf("gap should resolve to transition, got %v", gap)
+	}
+	overlap := Date(2024, 11, 3, 1, 30, 0, 0, ny)
+	if want := time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC); !overlap.Equal(want) {
+		t.Fatalf("overlap should resolve to first occurrence, got %v", overlap.UTC())
+	}
+	if !IsNonexistent(2024, 3, 10, 2, 30, 0, ny) || IsNonexistent(2024, 3, 10, 3, 30, 0, ny) {
+		t.Fatalf("nonexistent detection failed")
+	}
+	if !IsAmbiguous(2024, 11, 3, 1, 30, 0, ny) || IsAmbiguous(2024, 11, 3, 2, 30, 0, ny) || IsAmbiguous(2024, 3, 10, 2, 30, 0, ny) {
+		t.Fatalf("ambiguous detection failed")
+	}
+	if got := Date(2024, 1, 2, 9, 0, 0, 0, time.UTC); !got.Equal(time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("fixed zones should match time.Date")
+	}
+}
+
+func TestAddDaysAcrossMidnightTransition(t *testing.T) {
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Skip("timezone DB unavailable")
+	}
+	// Clocks jump from 00:00 to 01:00 on 2024-09-08, so that day has no midnight.
+	day := time.Date(2024, 9, 7, 12, 0, 0, 0, santiago)
+	next := AddDays(day, 1, santiago)
+	if y, m, d := next.Date(); y != 2024 || m != 9 || d != 8 {
+		t.Fatalf("expected Sep 8, got %v", next)
+	}
+	if start := StartOfDay(next.Add(5*time.Hour), santiago); !start.Equal(next) {
+		t.Fatalf("start of day mismatch: %v vs %v", start, next)
+	}
+	if eod := EndOfDay(day, santiago); !eod.Equal(next.Add(-time.Nanosecond)) {
+		t.Fatalf("end of day should precede next day start, got %v", eod)
+	}
+}
@@ -1,13 +1,14 @@
 package validate
 
 import (
-	"errors"
 	"time"
+
+	"github.com/Melpic13/timeslot/internal/errs"
 )
 
 var (
-	ErrInvalidTimeRange = errors.New("end time must be after start time")
-	ErrInvalidDuration  = errors.New("duration must be positive")
+	ErrInvalidTimeRange = errs.ErrInvalidTimeRange
+	ErrInvalidDuration  = errs.ErrInvalidDuration
 )
 
 func TimeRange(start, end time.Time) error {
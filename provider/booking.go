@@ -0,0 +1,243 @@
+package provider
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/Melpic13/timeslot/internal/errs"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// BookingStatus is the lifecycle state of a Booking.
+type BookingStatus string
+
+const (
+	StatusHeld      BookingStatus = "held"
+	StatusConfirmed BookingStatus = "confirmed"
+	StatusCancelled BookingStatus = "cancelled"
+	StatusNoShow    BookingStatus = "no_show"
+	// StatusExpired marks a hold that lapsed before being confirmed.
+	StatusExpired BookingStatus = "expired"
+)
+
+// Booking is a booking record. Held and confirmed bookings occupy their
+// slot; the other statuses are kept for history only, except no-shows,
+// which keep the slot since the time was reserved.
+type Booking struct {
+	ID         string         `json:"id"`
+	ProviderID string         `json:"provider_id"`
+	Slot       slot.TimeSlot  `json:"slot"`
+	Status     BookingStatus  `json:"status"`
+	Customer   map[string]any `json:"customer,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	ExpiresAt  time.Time      `json:"expires_at,omitempty"`
+}
+
+// Expired reports whether b is a hold whose TTL has passed at now.
+func (b Booking) Expired(now time.Time) bool {
+	return b.Status == StatusHeld && !b.ExpiresAt.IsZero() && !now.Before(b.ExpiresAt)
+}
+
+// Hold reserves s for ttl. The slot is taken through the regular Book path
+// and released again if the hold is not confirmed in time.
+func (p *Provider) Hold(s slot.TimeSlot, ttl time.Duration, customer map[string]any) (*Provider, Booking, error) {
+	if ttl <= 0 {
+		return nil, Booking{}, fmt.Errorf("%w: hold ttl %v", errs.ErrInvalidDuration, ttl)
+	}
+	return p.createBooking(s, StatusHeld, ttl, customer)
+}
+
+// CreateBooking books s and records it as confirmed.
+func (p *Provider) CreateBooking(s slot.TimeSlot, customer map[string]any) (*Provider, Booking, error) {
+	return p.createBooking(s, StatusConfirmed, 0, customer)
+}
+
+func (p *Provider) createBooking(s slot.TimeSlot, status BookingStatus, ttl time.Duration, customer map[string]any) (*Provider, Booking, error) {
+	var b Booking
+	booked, err := p.write(func(cur *Provider) (*Provider, error) {
+		next, err := cur.book(s, cur.rules())
+		if err != nil {
+			return nil, err
+		}
+		now := next.Now()
+		b = Booking{
+			ID:         newBookingID(),
+			ProviderID: p.ID,
+			Slot:       s,
+			Status:     status,
+			Customer:   copyMetadata(customer),
+			CreatedAt:  now,
+		}
+		if ttl > 0 {
+			b.ExpiresAt = now.Add(ttl)
+		}
+		next.Records = append(next.Records, b)
+		return next, nil
+	})
+	if err != nil {
+		return nil, Booking{}, err
+	}
+	return booked, b, nil
+}
+
+// Confirm turns a live hold into a confirmed booking.
+func (p *Provider) Confirm(id string) (*Provider, Booking, error) {
+	return p.updateRecord(id, func(cur *Provider, b Booking) (*Provider, Booking, error) {
+		if b.Status == StatusExpired {
+			return nil, b, errs.Wrap("confirm", b.Slot, ErrHoldExpired)
+		}
+		if b.Status != StatusHeld {
+			return nil, b, fmt.Errorf("%w: cannot confirm %s booking", ErrInvalidStatus, b.Status)
+		}
+		b.Status = StatusConfirmed
+		b.ExpiresAt = time.Time{}
+		return cur.clone(), b, nil
+	})
+}
+
+// Cancel releases the slot of a held or confirmed booking. Holds past their
+// TTL have already expired and return ErrHoldExpired.
+func (p *Provider) Cancel(id string) (*Provider, Booking, error) {
+	return p.updateRecord(id, func(cur *Provider, b Booking) (*Provider, Booking, error) {
+		if b.Status == StatusExpired {
+			return nil, b, errs.Wrap("cancel", b.Slot, ErrHoldExpired)
+		}
+		if b.Status != StatusHeld && b.Status != StatusConfirmed {
+			return nil, b, fmt.Errorf("%w: cannot cancel %s booking", ErrInvalidStatus, b.Status)
+		}
+		next, err := cur.cancel(b.Slot)
+		b.Status = StatusCancelled
+		return next, b, err
+	})
+}
+
+// MarkNoShow records that the customer of a confirmed booking did not
+// attend. The slot stays taken.
+func (p *Provider) MarkNoShow(id string) (*Provider, Booking, error) {
+	return p.updateRecord(id, func(cur *Provider, b Booking) (*Provider, Booking, error) {
+		if b.Status != StatusConfirmed {
+			return nil, b, fmt.Errorf("%w: cannot mark %s booking as no-show", ErrInvalidStatus, b.Status)
+		}
+		b.Status = StatusNoShow
+		return cur.clone(), b, nil
+	})
+}
+
+// updateRecord writes the record with id as returned by change, along with
+// the provider change returns.
+func (p *Provider) updateRecord(id string, change func(cur *Provider, b Booking) (*Provider, Booking, error)) (*Provider, Booking, error) {
+	var b Booking
+	out, err := p.write(func(cur *Provider) (*Provider, error) {
+		i, ok := cur.bookingIndex(id)
+		if !ok {
+			return nil, ErrBookingNotFound
+		}
+		next, updated, err := change(cur, cur.Records[i])
+		if err != nil {
+			return nil, err
+		}
+		b = updated
+		next.Records[i] = b
+		return next, nil
+	})
+	if err != nil {
+		return nil, Booking{}, err
+	}
+	return out, b, nil
+}
+
+// ExpireHolds returns a copy in which every hold past its TTL is marked
+// expired and its slot released. Store-backed providers save the result.
+func (p *Provider) ExpireHolds() (*Provider, error) {
+	return p.write(func(cur *Provider) (*Provider, error) {
+		return cur, nil
+	})
+}
+
+// current returns p with the holds past their TTL marked expired and their
+// slots released, or p itself when none has lapsed, so expired holds never
+// block a slot.
+func (p *Provider) current() *Provider {
+	now := p.Now()
+	out := p
+	for i, b := range p.Records {
+		if !b.Expired(now) {
+			continue
+		}
+		if out == p {
+			out = p.clone()
+		}
+		out.removeBooking(b.Slot)
+		out.Records[i].Status = StatusExpired
+	}
+	return out
+}
+
+// Booking returns the record with id. Holds past their TTL report
+// StatusExpired. A store that cannot be read finds nothing.
+func (p *Provider) Booking(id string) (Booking, bool) {
+	cur, err := p.read()
+	if err != nil {
+		return Booking{}, false
+	}
+	i, ok := cur.bookingIndex(id)
+	if !ok {
+		return Booking{}, false
+	}
+	return cur.Records[i], true
+}
+
+// FindBookings returns the records whose status is one of statuses, or all
+// records when none are given. Holds past their TTL report StatusExpired.
+func (p *Provider) FindBookings(statuses ...BookingStatus) []Booking {
+	cur, err := p.read()
+	if err != nil {
+		return nil
+	}
+	var out []Booking
+	for _, b := range cur.Records {
+		if len(statuses) == 0 || hasStatus(statuses, b.Status) {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+func (p *Provider) bookingIndex(id string) (int, bool) {
+	for i, b := range p.Records {
+		if b.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func hasStatus(statuses []BookingStatus, status BookingStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func copyMetadata(in map[string]any) map[string]any {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]any, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
+func newBookingID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("bk-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b[:])
+}
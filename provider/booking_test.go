@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/clock"
+	"github.com/Melpic13/timeslot/query"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+func TestBookingLifecycle(t *testing.T) {
+	p, monday := capacityFixture()
+	clk := clock.NewManual(monday)
+	p.Clock = clk
+	s := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+
+	held, hold, err := p.Hold(s, 10*time.Minute, map[string]any{"email": "a@example.com"})
+	if err != nil {
+		t.Fatalf("hold: %v", err)
+	}
+	if hold.ID == "" || hold.Status != StatusHeld || !hold.ExpiresAt.Equal(monday.Add(10*time.Minute)) || hold.ProviderID != "studio" {
+		t.Fatalf("unexpected hold %+v", hold)
+	}
+	if held.IsAvailable(s) {
+		t.Fatalf("a hold should take the slot")
+	}
+	if _, _, err := held.CreateBooking(s, nil); err == nil {
+		t.Fatalf("expected held slot to reject another booking")
+	}
+
+	confirmed, b, err := held.Confirm(hold.ID)
+	if err != nil || b.Status != StatusConfirmed || !b.ExpiresAt.IsZero() {
+		t.Fatalf("confirm: %+v (%v)", b, err)
+	}
+	if got, _ := held.Booking(hold.ID); got.Status != StatusHeld {
+		t.Fatalf("confirm must not mutate the original provider")
+	}
+	if _, _, err := confirmed.Confirm(hold.ID); err == nil {
+		t.Fatalf("expected error confirming twice")
+	}
+
+	noShow, b, err := confirmed.MarkNoShow(hold.ID)
+	if err != nil || b.Status != StatusNoShow || noShow.IsAvailable(s) {
+		t.Fatalf("no-show should keep the slot: %+v (%v)", b, err)
+	}
+	if _, _, err := noShow.Cancel(hold.ID); err == nil {
+		t.Fatalf("expected error cancelling a no-show")
+	}
+
+	cancelled, b, err := confirmed.Cancel(hold.ID)
+	if err != nil || b.Status != StatusCancelled || !cancelled.IsAvailable(s) {
+		t.Fatalf("cancel should free the slot: %+v (%v)", b, err)
+	}
+	if _, _, err := cancelled.Cancel("missing"); !errors.Is(err, ErrBookingNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestHoldExpiry(t *testing.T) {
+	p, monday := capacityFixture()
+	clk := clock.NewManual(monday)
+	p.Clock = clk
+	s := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+
+	held, hold, err := p.Hold(s, 10*time.Minute, nil)
+	if err != nil {
+		t.Fatalf("hold: %v", err)
+	}
+	clk.Advance(10 * time.Minute)
+	if _, _, err := held.Confirm(hold.ID); !errors.Is(err, ErrHoldExpired) {
+		t.Fatalf("expected expired hold, got %v", err)
+	}
+	if _, _, err := held.Cancel(hold.ID); !errors.Is(err, ErrHoldExpired) {
+		t.Fatalf("expected an expired hold to refuse cancelling, got %v", err)
+	}
+	if got, _ := held.Booking(hold.ID); got.Status != StatusExpired {
+		t.Fatalf("expired hold should report expired, got %s", got.Status)
+	}
+	if got := held.FindBookings(StatusHeld); len(got) != 0 {
+		t.Fatalf("expired holds should not be listed as held")
+	}
+
+	if !held.IsAvailable(s) {
+		t.Fatalf("a lapsed hold should not block its slot")
+	}
+	if _, err := held.Book(s); err != nil {
+		t.Fatalf("book after the hold lapsed: %v", err)
+	}
+	q := query.NewQuery().Duration(time.Hour).Between(monday, monday.Add(24*time.Hour)).Build()
+	if slots, err := held.FindSlots(q); err != nil || len(slots) != 3 || !slots[0].Start.Equal(s.Start) {
+		t.Fatalf("expected the released slot among 3 candidates, got %v (%v)", slots, err)
+	}
+
+	// A new booking first releases the lapsed hold.
+	rebooked, b, err := held.CreateBooking(s, nil)
+	if err != nil || b.Status != StatusConfirmed {
+		t.Fatalf("expected lapsed hold to be released: %v", err)
+	}
+	if got := rebooked.FindBookings(StatusExpired); len(got) != 1 || got[0].ID != hold.ID {
+		t.Fatalf("expected the hold to be recorded as expired, got %+v", got)
+	}
+	if got := rebooked.FindBookings(); len(got) != 2 {
+		t.Fatalf("expected two records, got %d", len(got))
+	}
+	if _, _, err := p.Hold(s, 0, nil); err == nil {
+		t.Fatalf("expected error for zero ttl")
+	}
+}
+
+func TestBookingRecordsWithCapacity(t *testing.T) {
+	p, monday := capacityFixture(WithCapacity(2))
+	s := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+	p, first, err := p.CreateBooking(s, map[string]any{"name": "ana"})
+	if err != nil {
+		t.Fatalf("first: %v", err)
+	}
+	p, second, err := p.CreateBooking(s, map[string]any{"name": "ben"})
+	if err != nil || first.ID == second.ID {
+		t.Fatalf("expected two distinct bookings (%v)", err)
+	}
+	p, _, err = p.Cancel(first.ID)
+	if err != nil || p.RemainingSeats(s) != 1 {
+		t.Fatalf("cancelling by id should free exactly one seat (%v)", err)
+	}
+	if got, _ := p.Booking(second.ID); got.Status != StatusConfirmed || got.Customer["name"] != "ben" {
+		t.Fatalf("other booking should be untouched, got %+v", got)
+	}
+	data, err := json.Marshal(second)
+	if err != nil || !strings.Contains(string(data), `"status":"confirmed"`) {
+		t.Fatalf("unexpected encoding %s (%v)", data, err)
+	}
+}
+
+func TestBookingRecordsPersistInStore(t *testing.T) {
+	store := NewMemoryStore()
+	p, monday := capacityFixture(WithStore(store))
+	clk := clock.NewManual(monday)
+	p.Clock = clk
+	s := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+
+	_, hold, err := p.Hold(s, 10*time.Minute, map[string]any{"name": "ada"})
+	if err != nil {
+		t.Fatalf("hold: %v", err)
+	}
+	// The stale provider value reads and updates the records in the store.
+	if got, ok := p.Booking(hold.ID); !ok || got.Status != StatusHeld || got.Customer["name"] != "ada" {
+		t.Fatalf("expected the stored hold, got %+v", got)
+	}
+	if _, _, err := p.Hold(s, time.Minute, nil); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected the held slot to be taken, got %v", err)
+	}
+	confirmed, b, err := p.Confirm(hold.ID)
+	if err != nil || b.Status != StatusConfirmed || confirmed.Version != 2 {
+		t.Fatalf("confirm: %+v (%v)", b, err)
+	}
+	state, _ := store.Load(p.ID)
+	if len(state.Records) != 1 || state.Records[0].Status != StatusConfirmed || len(state.Bookings) != 1 {
+		t.Fatalf("unexpected stored state %+v", state)
+	}
+
+	next := s.Shift(time.Hour)
+	_, lapsing, err := p.Hold(next, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("hold: %v", err)
+	}
+	clk.Advance(time.Minute)
+	if !p.IsAvailable(next) {
+		t.Fatalf("a lapsed stored hold should not block its slot")
+	}
+	if _, err := p.ExpireHolds(); err != nil {
+		t.Fatalf("expire: %v", err)
+	}
+	state, _ = store.Load(p.ID)
+	if len(state.Bookings) != 1 || state.Records[1].ID != lapsing.ID || state.Records[1].Status != StatusExpired {
+		t.Fatalf("expected the lapsed hold to be saved as expired, got %+v", state)
+	}
+	if _, _, err := p.Cancel(hold.ID); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if state, _ = store.Load(p.ID); len(state.Bookings) != 0 || state.Records[0].Status != StatusCancelled {
+		t.Fatalf("expected the cancellation to be saved, got %+v", state)
+	}
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// MetadataSeatsRemaining is the metadata key FindSlots uses to report the
+// free seats of a candidate on capacity-based providers.
+const MetadataSeatsRemaining = "seats_remaining"
+
+// CapacityRule overrides the provider capacity between Start and End. When
+// rules overlap, the first one listed wins.
+type CapacityRule struct {
+	Start time.Time
+	End   time.Time
+	Seats int
+}
+
+// HasCapacity reports whether the provider books by seat rather than one
+// booking at a time. It is true once Capacity exceeds one or any capacity
+// rule is set.
+func (p *Provider) HasCapacity() bool {
+	return p.Capacity > 1 || len(p.CapacityRules) > 0
+}
+
+// CapacityAt returns the number of seats offered at t.
+func (p *Provider) CapacityAt(t time.Time) int {
+	for _, r := range p.CapacityRules {
+		if !t.Before(r.Start) && t.Before(r.End) {
+			return r.Seats
+		}
+	}
+	if p.Capacity < 1 {
+		return 1
+	}
+	return p.Capacity
+}
+
+// RemainingSeats returns the seats still free for all of s, i.e. the
+// minimum over s of capacity minus overlapping seat bookings. Buffers are
+// honoured as for providers without capacity: a booking takes its seat for
+// its buffers too, and s needs a seat for the provider's buffers around it.
+// A store that cannot be read leaves no seats.
+func (p *Provider) RemainingSeats(s slot.TimeSlot) int {
+	if !p.HasCapacity() {
+		if p.IsAvailable(s) {
+			return 1
+		}
+		return 0
+	}
+	cur, err := p.read()
+	if err != nil {
+		return 0
+	}
+	return cur.remainingSeats(s, p.rules())
+}
+
+// remainingSeats counts the seats free for s booked under r. Inside s every
+// booking occupies its buffered span; in the buffers of s only the booked
+// time of other bookings counts, so buffers may overlap each other.
+func (p *Provider) remainingSeats(s slot.TimeSlot, r bookingRules) int {
+	remaining := -1
+	take := func(ivs []seatInterval, buffer bool) {
+		for _, iv := range ivs {
+			if buffer && iv.taken == 0 {
+				continue
+			}
+			if free := iv.capacity - iv.taken; remaining < 0 || free < remaining {
+				remaining = free
+			}
+		}
+	}
+	span := r.expand(s)
+	take(p.seatProfile(s.Start, s.End, true), false)
+	take(p.seatProfile(span.Start, s.Start, false), true)
+	take(p.seatProfile(s.End, span.End, false), true)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+type seatInterval struct {
+	start, end      time.Time
+	taken, capacity int
+}
+
+// seatProfile splits [from, to) wherever a seat booking or capacity rule
+// starts or ends, so that seats taken and capacity are constant inside
+// each interval. With buffered set, bookings span their buffers too.
+func (p *Provider) seatProfile(from, to time.Time, buffered bool) []seatInterval {
+	if !to.After(from) {
+		return nil
+	}
+	points := []time.Time{from, to}
+	add := func(t time.Time) {
+		if t.After(from) && t.Before(to) {
+			points = append(points, t)
+		}
+	}
+	spans := make([]slot.TimeSlot, len(p.SeatBookings))
+	for i, b := range p.SeatBookings {
+		spans[i] = b
+		if buffered {
+			_, r := p.rulesFor(b)
+			spans[i] = r.expand(b)
+		}
+		add(spans[i].Start)
+		add(spans[i].End)
+	}
+	for _, r := range p.CapacityRules {
+		add(r.Start)
+		add(r.End)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Before(points[j]) })
+	var out []seatInterval
+	for i := 0; i+1 < len(points); i++ {
+		start, end := points[i], points[i+1]
+		if !end.After(start) {
+			continue
+		}
+		iv := seatInterval{start: start, end: end, capacity: p.CapacityAt(start)}
+		for _, b := range spans {
+			if b.Start.Before(end) && b.End.After(start) {
+				iv.taken++
+			}
+		}
+		out = append(out, iv)
+	}
+	return out
+}
+
+// refreshCapacity rebuilds Availability.Bookings as the periods in which
+// every seat is taken, so availability queries skip full sessions.
+func (p *Provider) refreshCapacity() {
+	var from, to time.Time
+	extend := func(start, end time.Time) {
+		if from.IsZero() || start.Before(from) {
+			from = start
+		}
+		if to.IsZero() || end.After(to) {
+			to = end
+		}
+	}
+	for _, b := range p.SeatBookings {
+		extend(b.Start, b.End)
+	}
+	for _, r := range p.CapacityRules {
+		extend(r.Start, r.End)
+	}
+	var full []slot.TimeSlot
+	for _, iv := range p.seatProfile(from, to, false) {
+		if iv.taken >= iv.capacity {
+			full = append(full, slot.TimeSlot{Start: iv.start, End: iv.end, Location: p.locationOrUTC()})
+		}
+	}
+	p.Availability.Bookings = slot.NewCollection(full...)
+}
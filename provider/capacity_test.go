@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/availability"
+	"github.com/Melpic13/timeslot/clock"
+	"github.com/Melpic13/timeslot/query"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+func capacityFixture(opts ...ProviderOption) (*Provider, time.Time) {
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	ws := availability.NewWeeklySchedule(time.UTC).SetDay(time.Monday, availability.TimeRange{Start: availability.NewTimeOfDay(9, 0, 0), End: availability.NewTimeOfDay(12, 0, 0)})
+	base := []ProviderOption{WithWeeklySchedule(ws), WithClock(clock.Fixed(monday))}
+	return NewProvider("studio", append(base, opts...)...), monday
+}
+
+func TestCapacityBookUntilFull(t *testing.T) {
+	p, monday := capacityFixture(WithCapacity(3))
+	class := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+
+	var err error
+	for i := 3; i > 0; i-- {
+		if got := p.RemainingSeats(class); got != i {
+			t.Fatalf("expected %d seats before booking, got %d", i, got)
+		}
+		if p, err = p.Book(class); err != nil {
+			t.Fatalf("seat %d: unexpected error %v", 4-i, err)
+		}
+	}
+	if _, err := p.Book(class); err == nil {
+		t.Fatalf("expected class to be full")
+	}
+	if p.IsAvailable(class) || len(p.GetBookings(monday, monday.Add(24*time.Hour))) != 3 {
+		t.Fatalf("expected three seat bookings and no availability")
+	}
+	if free := p.Availability.GetSlots(monday, monday.Add(24*time.Hour)); free.TotalDuration() != 2*time.Hour {
+		t.Fatalf("full session should be removed from availability, got %v", free.TotalDuration())
+	}
+
+	// A class overlapping half of the full one cannot be booked either.
+	shifted := slot.TimeSlot{Start: monday.Add(9*time.Hour + 30*time.Minute), End: monday.Add(10*time.Hour + 30*time.Minute), Location: time.UTC}
+	if p.RemainingSeats(shifted) != 0 {
+		t.Fatalf("expected no seats for an overlapping slot")
+	}
+
+	freed, err := p.CancelBooking(class)
+	if err != nil || freed.RemainingSeats(class) != 1 || !freed.IsAvailable(class) {
+		t.Fatalf("cancellation should free a seat (err=%v)", err)
+	}
+	if p.RemainingSeats(class) != 0 {
+		t.Fatalf("cancel must not mutate the original provider")
+	}
+	if _, err := freed.CancelBooking(slot.TimeSlot{Start: monday, End: monday.Add(time.Hour), Location: time.UTC}); err == nil {
+		t.Fatalf("expected not found for unknown seat")
+	}
+}
+
+func TestCapacityRulesAndFindSlots(t *testing.T) {
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	p, _ := capacityFixture(WithCapacity(2), WithCapacityRule(monday.Add(11*time.Hour), monday.Add(12*time.Hour), 5))
+	if p.CapacityAt(monday.Add(9*time.Hour)) != 2 || p.CapacityAt(monday.Add(11*time.Hour)) != 5 {
+		t.Fatalf("unexpected capacities")
+	}
+	first := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+	p, err := p.Book(first)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	q := query.NewQuery().Duration(time.Hour).Between(monday, monday.Add(24*time.Hour)).Build()
+	slots, err := p.FindSlots(q)
+	if err != nil || len(slots) != 3 {
+		t.Fatalf("expected 3 candidates, got %d (%v)", len(slots), err)
+	}
+	for i, want := range []int{1, 2, 5} {
+		if got := slots[i].Metadata[MetadataSeatsRemaining]; got != want {
+			t.Fatalf("candidate %d: expected %d seats, got %v", i, want, got)
+		}
+	}
+
+	closed, _ := capacityFixture(WithCapacityRule(monday.Add(9*time.Hour), monday.Add(10*time.Hour), 0))
+	if !closed.HasCapacity() || closed.IsAvailable(first) {
+		t.Fatalf("a zero-seat rule should close its range")
+	}
+	single, _ := capacityFixture()
+	if single.HasCapacity() || single.RemainingSeats(first) != 1 {
+		t.Fatalf("providers without capacity should report one seat")
+	}
+}
+
+func TestCapacityHonoursBuffers(t *testing.T) {
+	p, monday := capacityFixture(WithCapacity(2), WithBufferAfter(30*time.Minute))
+	class := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+	next := slot.TimeSlot{Start: monday.Add(10 * time.Hour), End: monday.Add(11 * time.Hour), Location: time.UTC}
+	later := slot.TimeSlot{Start: monday.Add(10*time.Hour + 30*time.Minute), End: monday.Add(11*time.Hour + 30*time.Minute), Location: time.UTC}
+
+	var err error
+	for i := 0; i < 2; i++ {
+		if p, err = p.Book(class); err != nil {
+			t.Fatalf("seat %d: unexpected error %v", i+1, err)
+		}
+	}
+	if p.RemainingSeats(next) != 0 || p.IsAvailable(next) {
+		t.Fatalf("both seats are still in their buffer at 10:00")
+	}
+	if _, err := p.Book(next); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict inside the buffers, got %v", err)
+	}
+	if p.RemainingSeats(later) != 2 {
+		t.Fatalf("expected both seats free after the buffer, got %d", p.RemainingSeats(later))
+	}
+
+	// The buffer after a new booking needs a seat as well.
+	early := slot.TimeSlot{Start: monday.Add(8*time.Hour + 30*time.Minute), End: monday.Add(9 * time.Hour), Location: time.UTC}
+	if p.RemainingSeats(early) != 0 {
+		t.Fatalf("the buffer of an 08:30 booking runs into the full class")
+	}
+}
+
+func TestCapacityFindSlotsSkipsBufferedSeats(t *testing.T) {
+	p, monday := capacityFixture(WithCapacity(2), WithBufferAfter(15*time.Minute))
+	class := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+	var err error
+	for i := 0; i < 2; i++ {
+		if p, err = p.Book(class); err != nil {
+			t.Fatalf("seat %d: unexpected error %v", i+1, err)
+		}
+	}
+	q := query.NewQuery().Duration(time.Hour).Step(15*time.Minute).Between(monday, monday.Add(24*time.Hour)).Build()
+	slots, err := p.FindSlots(q)
+	if err != nil || len(slots) == 0 || !slots[0].Start.Equal(monday.Add(10*time.Hour+15*time.Minute)) {
+		t.Fatalf("expected candidates from 10:15 on, got %v (%v)", slots, err)
+	}
+	for _, s := range slots {
+		if seats := s.Metadata[MetadataSeatsRemaining]; seats == 0 {
+			t.Fatalf("%v offered without a free seat", s)
+		}
+		if _, err := p.Book(s); err != nil {
+			t.Fatalf("candidate %v could not be booked: %v", s, err)
+		}
+	}
+}
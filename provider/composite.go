@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Melpic13/timeslot/internal/errs"
+	"github.com/Melpic13/timeslot/query"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// MetadataResources is the metadata key Composite.FindSlots uses to list,
+// in requirement order, the IDs of the providers a candidate would book.
+const MetadataResources = "resources"
+
+// Requirement is one resource a composite booking needs: a given provider,
+// or any one member of a pool.
+type Requirement struct {
+	Provider *Provider
+	Pool     *Pool
+}
+
+// Require needs p itself.
+func Require(p *Provider) Requirement {
+	return Requirement{Provider: p}
+}
+
+// RequireAny needs one free member of pool, chosen by its strategy.
+func RequireAny(pool *Pool) Requirement {
+	return Requirement{Pool: pool}
+}
+
+// Composite books several resources for the same slot, such as a surgeon,
+// an anaesthetist and any free operating room. Each resource keeps its own
+// buffers, booking window and limits. Like Provider, its methods return
+// updated copies.
+type Composite struct {
+	Requirements []Requirement
+}
+
+func NewComposite(reqs ...Requirement) *Composite {
+	return &Composite{Requirements: reqs}
+}
+
+// FindSlots returns the candidates every requirement can take, listing the
+// providers they would book under MetadataResources. Queries naming a
+// service are not supported.
+func (c *Composite) FindSlots(q query.Query) ([]slot.TimeSlot, error) {
+	if q.Service != "" {
+		return nil, fmt.Errorf("provider: composite queries cannot name a service")
+	}
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	synced, err := c.sync()
+	if err != nil {
+		return nil, err
+	}
+	if len(synced.Requirements) == 0 {
+		return nil, nil
+	}
+	var free slot.SlotCollection
+	for i, r := range synced.Requirements {
+		var reqFree slot.SlotCollection
+		for _, p := range r.providers() {
+			reqFree = reqFree.Union(p.Availability.GetSlots(q.From, q.To))
+		}
+		if i == 0 {
+			free = reqFree
+		} else {
+			free = free.Intersect(reqFree)
+		}
+	}
+	var out []slot.TimeSlot
+	it := q.Generator().Iterate(free)
+	for candidate, ok := it.Next(); ok; candidate, ok = it.Next() {
+		if !(*Provider).passesConstraints(nil, candidate, q.Constraints) {
+			continue
+		}
+		resources, err := synced.assign(candidate, "")
+		if err != nil {
+			continue
+		}
+		ids := make([]string, len(resources))
+		for i, p := range resources {
+			ids[i] = p.ID
+		}
+		meta := copyMetadata(candidate.Metadata)
+		if meta == nil {
+			meta = map[string]any{}
+		}
+		meta[MetadataResources] = ids
+		candidate.Metadata = meta
+		out = append(out, candidate)
+	}
+	out = query.OptimizeSlots(out, q)
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out, nil
+}
+
+// Book books s with every requirement, or with none of them. It returns the
+// updated composite and the IDs of the providers booked in requirement
+// order. Bookings already written to a store are cancelled again when a
+// later requirement fails; cancellations that fail are joined to the error,
+// naming the providers left booked.
+func (c *Composite) Book(s slot.TimeSlot, customer string) (*Composite, []string, error) {
+	synced, err := c.sync()
+	if err != nil {
+		return nil, nil, err
+	}
+	picks, err := synced.assign(s, customer)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Requirements sharing a provider book it in turn, each against the
+	// copy the previous one returned.
+	booked := map[string]*Provider{}
+	ids := make([]string, len(picks))
+	var stored []*Provider
+	for i, pick := range picks {
+		cur := pick
+		if b, ok := booked[pick.ID]; ok {
+			cur = b
+		}
+		next, err := cur.Book(s)
+		if err != nil {
+			var left []string
+			var failed []error
+			for _, p := range stored {
+				if _, cerr := p.CancelBooking(s); cerr != nil {
+					left = append(left, p.ID)
+					failed = append(failed, cerr)
+				}
+			}
+			if len(left) > 0 {
+				err = errors.Join(err, fmt.Errorf("provider: rollback left %s booked: %w", strings.Join(left, ", "), errors.Join(failed...)))
+			}
+			return nil, nil, err
+		}
+		booked[pick.ID] = next
+		ids[i] = pick.ID
+		if next.Store != nil {
+			stored = append(stored, next)
+		}
+	}
+	out := &Composite{Requirements: make([]Requirement, len(c.Requirements))}
+	pools := map[*Pool]*Pool{}
+	for i, r := range c.Requirements {
+		if r.Pool == nil {
+			out.Requirements[i].Provider = booked[r.Provider.ID]
+			continue
+		}
+		pool, ok := pools[r.Pool]
+		if !ok {
+			pool = r.Pool.clone()
+			for j, m := range pool.Members {
+				if b, ok := booked[m.ID]; ok {
+					pool.Members[j] = b
+				}
+			}
+			pools[r.Pool] = pool
+		}
+		pool.Last = ids[i]
+		if customer != "" {
+			pool.History[customer] = ids[i]
+		}
+		out.Requirements[i].Pool = pool
+	}
+	return out, ids, nil
+}
+
+// assign returns the provider each requirement would book for s, or the
+// first requirement's reason for refusing it. Requirements are checked in
+// order against working copies that already hold the earlier picks, so a
+// provider is not counted twice and pools only offer members not yet
+// assigned.
+func (c *Composite) assign(s slot.TimeSlot, customer string) ([]*Provider, error) {
+	working := map[string]*Provider{}
+	current := func(p *Provider) *Provider {
+		if w, ok := working[p.ID]; ok {
+			return w
+		}
+		return p.current()
+	}
+	out := make([]*Provider, len(c.Requirements))
+	for i, r := range c.Requirements {
+		if r.Pool != nil {
+			var free []*Provider
+			var firstErr error
+			for _, m := range r.Pool.Members {
+				if _, taken := working[m.ID]; taken {
+					continue
+				}
+				if err := current(m).checkBookable(s, m.rules()); err == nil {
+					free = append(free, m)
+				} else if firstErr == nil {
+					firstErr = err
+				}
+			}
+			if len(free) == 0 {
+				if firstErr == nil {
+					firstErr = ErrNoAvailability
+				}
+				return nil, errs.Wrap("book", s, firstErr)
+			}
+			out[i] = r.Pool.strategy()(r.Pool, s, customer, free)
+		} else {
+			if err := current(r.Provider).checkBookable(s, r.Provider.rules()); err != nil {
+				return nil, errs.Wrap("book", s, err)
+			}
+			out[i] = r.Provider
+		}
+		w := current(out[i]).clone()
+		w.addBooking(s)
+		working[out[i].ID] = w
+	}
+	return out, nil
+}
+
+// sync reloads the bookings of store-backed providers.
+func (c *Composite) sync() (*Composite, error) {
+	out := &Composite{Requirements: make([]Requirement, len(c.Requirements))}
+	for i, r := range c.Requirements {
+		if r.Pool != nil {
+			pool := r.Pool.clone()
+			for j, m := range pool.Members {
+				synced, err := m.Sync()
+				if err != nil {
+					return nil, err
+				}
+				pool.Members[j] = synced.current()
+			}
+			out.Requirements[i].Pool = pool
+			continue
+		}
+		synced, err := r.Provider.Sync()
+		if err != nil {
+			return nil, err
+		}
+		out.Requirements[i].Provider = synced.current()
+	}
+	return out, nil
+}
+
+func (r Requirement) providers() []*Provider {
+	if r.Pool != nil {
+		return r.Pool.Members
+	}
+	return []*Provider{r.Provider}
+}
@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/query"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// theatre returns a surgeon with a 30-minute cleanup booked 09:00-10:00,
+// an anaesthetist and two operating rooms, the first booked 10:30-11:30.
+// Everyone is open Monday 09:00-12:00.
+func theatre(t *testing.T, opts ...ProviderOption) (*Composite, time.Time) {
+	t.Helper()
+	surgeon, monday := capacityFixture(append([]ProviderOption{WithBufferAfter(30 * time.Minute)}, opts...)...)
+	surgeon.ID = "surgeon"
+	surgeon, err := surgeon.Book(at(monday, 9*time.Hour, time.Hour))
+	if err != nil {
+		t.Fatalf("book surgeon: %v", err)
+	}
+	anaesthetist, _ := capacityFixture(opts...)
+	anaesthetist.ID = "anaesthetist"
+	or1, _ := capacityFixture()
+	or1.ID = "or1"
+	if or1, err = or1.Book(at(monday, 10*time.Hour+30*time.Minute, time.Hour)); err != nil {
+		t.Fatalf("book room: %v", err)
+	}
+	or2, _ := capacityFixture()
+	or2.ID = "or2"
+	rooms := NewPool("rooms", nil, or1, or2)
+	return NewComposite(Require(surgeon), Require(anaesthetist), RequireAny(rooms)), monday
+}
+
+func TestCompositeFindSlots(t *testing.T) {
+	c, monday := theatre(t)
+	q := query.NewQuery().Duration(time.Hour).Step(30*time.Minute).Between(monday, monday.AddDate(0, 0, 1)).Build()
+	slots, err := c.FindSlots(q)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	// 10:00 is free for everyone on paper but inside the surgeon's cleanup.
+	want := []struct {
+		start     time.Duration
+		resources []string
+	}{
+		{10*time.Hour + 30*time.Minute, []string{"surgeon", "anaesthetist", "or2"}},
+		{11 * time.Hour, []string{"surgeon", "anaesthetist", "or2"}},
+	}
+	if len(slots) != len(want) {
+		t.Fatalf("expected %d candidates, got %v", len(want), slots)
+	}
+	for i, s := range slots {
+		if !s.Start.Equal(monday.Add(want[i].start)) || !reflect.DeepEqual(s.Metadata[MetadataResources], want[i].resources) {
+			t.Fatalf("candidate %d: expected %v at %v, got %v %v", i, want[i].resources, want[i].start, s, s.Metadata)
+		}
+	}
+	if _, err := c.FindSlots(query.NewQuery().Service("surgery").Between(monday, monday.AddDate(0, 0, 1)).Build()); err == nil {
+		t.Fatalf("expected service queries to be refused")
+	}
+}
+
+func TestCompositeBookAllOrNothing(t *testing.T) {
+	c, monday := theatre(t)
+	day := func(c *Composite, i int) []slot.TimeSlot {
+		var out []slot.TimeSlot
+		for _, p := range c.Requirements[i].providers() {
+			out = append(out, p.GetBookings(monday, monday.AddDate(0, 0, 1))...)
+		}
+		return out
+	}
+
+	if _, _, err := c.Book(at(monday, 10*time.Hour, time.Hour), "ada"); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected the surgeon's cleanup to refuse 10:00, got %v", err)
+	}
+	if len(day(c, 1)) != 0 {
+		t.Fatalf("a refused booking must not book anyone")
+	}
+
+	booked, ids, err := c.Book(at(monday, 11*time.Hour, time.Hour), "ada")
+	if err != nil {
+		t.Fatalf("book: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"surgeon", "anaesthetist", "or2"}) {
+		t.Fatalf("unexpected resources %v", ids)
+	}
+	for i := range booked.Requirements {
+		if got := len(day(booked, i)) - len(day(c, i)); got != 1 {
+			t.Fatalf("requirement %d: expected one new booking, got %d", i, got)
+		}
+	}
+}
+
+// failingStore accepts loads but refuses every write.
+type failingStore struct{ *MemoryStore }
+
+var errStoreDown = errors.New("store down")
+
+func (f *failingStore) Save(string, StoreState, int64) (int64, error) {
+	return 0, errStoreDown
+}
+
+func TestCompositeRollsBackStoredBookings(t *testing.T) {
+	store := NewMemoryStore()
+	anaesthetist, monday := capacityFixture(WithStore(store))
+	anaesthetist.ID = "anaesthetist"
+	surgeon, _ := capacityFixture(WithStore(&failingStore{NewMemoryStore()}))
+	surgeon.ID = "surgeon"
+
+	c := NewComposite(Require(anaesthetist), Require(surgeon))
+	if _, _, err := c.Book(at(monday, 9*time.Hour, time.Hour), ""); !errors.Is(err, errStoreDown) {
+		t.Fatalf("expected the surgeon's store error, got %v", err)
+	}
+	if state, _ := store.Load("anaesthetist"); len(state.Bookings) != 0 {
+		t.Fatalf("expected the anaesthetist booking to be rolled back, got %v", state.Bookings)
+	}
+}
+
+// saveOnceStore accepts the first write and refuses the later ones.
+type saveOnceStore struct {
+	*MemoryStore
+	saved bool
+}
+
+func (s *saveOnceStore) Save(id string, state StoreState, expected int64) (int64, error) {
+	if s.saved {
+		return 0, errStoreDown
+	}
+	s.saved = true
+	return s.MemoryStore.Save(id, state, expected)
+}
+
+func TestCompositeReportsFailedRollback(t *testing.T) {
+	store := &saveOnceStore{MemoryStore: NewMemoryStore()}
+	anaesthetist, monday := capacityFixture(WithStore(store))
+	anaesthetist.ID = "anaesthetist"
+	surgeon, _ := capacityFixture(WithStore(&failingStore{NewMemoryStore()}))
+	surgeon.ID = "surgeon"
+
+	_, _, err := NewComposite(Require(anaesthetist), Require(surgeon)).Book(at(monday, 9*time.Hour, time.Hour), "")
+	if !errors.Is(err, errStoreDown) || !strings.Contains(err.Error(), "rollback left anaesthetist booked") {
+		t.Fatalf("expected the failed rollback to be reported, got %v", err)
+	}
+	if state, _ := store.Load("anaesthetist"); len(state.Bookings) != 1 {
+		t.Fatalf("expected the anaesthetist booking to remain, got %v", state.Bookings)
+	}
+}
+
+func TestCompositeNeverDoubleBooks(t *testing.T) {
+	r1, monday := capacityFixture()
+	r1.ID = "r1"
+	r2, _ := capacityFixture()
+	r2.ID = "r2"
+	rooms := NewPool("rooms", RoundRobin, r1, r2)
+	s := at(monday, 9*time.Hour, time.Hour)
+
+	twoRooms := NewComposite(RequireAny(rooms), RequireAny(rooms))
+	booked, ids, err := twoRooms.Book(s, "ada")
+	if err != nil || !reflect.DeepEqual(ids, []string{"r1", "r2"}) {
+		t.Fatalf("expected both rooms, got %v (%v)", ids, err)
+	}
+	for _, id := range ids {
+		m, _ := booked.Requirements[1].Pool.Member(id)
+		if m.IsAvailable(s) {
+			t.Fatalf("%s should be booked in the returned pool", id)
+		}
+	}
+	if booked.Requirements[0].Pool.Last != "r2" {
+		t.Fatalf("expected the pool to remember r2 last, got %q", booked.Requirements[0].Pool.Last)
+	}
+	q := query.NewQuery().Duration(time.Hour).Between(monday, monday.AddDate(0, 0, 1)).Build()
+	slots, err := twoRooms.FindSlots(q)
+	if err != nil || len(slots) != 3 || !reflect.DeepEqual(slots[0].Metadata[MetadataResources], []string{"r1", "r2"}) {
+		t.Fatalf("expected distinct rooms for every candidate, got %v (%v)", slots, err)
+	}
+
+	if _, _, err := NewComposite(RequireAny(rooms), RequireAny(rooms), RequireAny(rooms)).Book(s, ""); !errors.Is(err, ErrNoAvailability) {
+		t.Fatalf("expected a third room to be missing, got %v", err)
+	}
+	if _, _, err := NewComposite(Require(r1), Require(r1)).Book(s, ""); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected r1 to be refused twice, got %v", err)
+	}
+	if _, ids, err := NewComposite(Require(r1), RequireAny(rooms)).Book(s, ""); err != nil || !reflect.DeepEqual(ids, []string{"r1", "r2"}) {
+		t.Fatalf("expected the pool to skip r1, got %v (%v)", ids, err)
+	}
+}
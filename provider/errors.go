@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"errors"
+
+	"github.com/Melpic13/timeslot/internal/errs"
+)
+
+// Booking errors shared with the root timeslot package. Failures tied to a
+// slot are returned as a *timeslot.SlotError wrapping one of these.
+var (
+	ErrNoAvailability     = errs.ErrNoAvailability
+	ErrConflict           = errs.ErrConflict
+	ErrPastTime           = errs.ErrPastTime
+	ErrInsufficientNotice = errs.ErrInsufficientNotice
+	ErrTooFarAdvance      = errs.ErrTooFarAdvance
+	ErrLimitExceeded      = errs.ErrLimitExceeded
+)
+
+var (
+	// ErrVersionConflict is returned when a store write carries a version
+	// that no longer matches the stored one.
+	ErrVersionConflict = errors.New("provider: booking store version conflict")
+	// ErrBookingNotFound is returned when a booking to remove does not exist.
+	ErrBookingNotFound = errors.New("provider: booking not found")
+	// ErrHoldExpired is returned when confirming a hold after its TTL.
+	ErrHoldExpired = errors.New("provider: hold expired")
+	// ErrInvalidStatus is returned when a booking cannot make the requested
+	// status transition.
+	ErrInvalidStatus = errors.New("provider: invalid booking status")
+	// ErrUnknownService is returned when a booking or query names a service
+	// the provider does not offer.
+	ErrUnknownService = errors.New("provider: unknown service")
+)
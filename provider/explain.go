@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"github.com/Melpic13/timeslot/availability"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// Explain reports whether s could be booked now and why. It extends
+// Availability.ExplainSlot with buffer, past, min-notice, max-advance and
+// limit checks, and its verdict matches Book.
+func (p *Provider) Explain(s slot.TimeSlot) availability.Explanation {
+	p, err := p.read()
+	if err != nil {
+		return availability.Explanation{Slot: s, Reasons: []availability.Reason{{Code: availability.ReasonStore, Message: err.Error()}}}
+	}
+	out := p.Availability.ExplainSlot(s)
+	add := func(code availability.ReasonCode, message string, sl *slot.TimeSlot) {
+		out.Reasons = append(out.Reasons, availability.Reason{Code: code, Message: message, Slot: sl})
+	}
+	now := p.Now()
+	inWindow := true
+	switch {
+	case s.Start.Before(now):
+		add(availability.ReasonPast, "starts in the past", nil)
+		inWindow = false
+	case p.MinNotice > 0 && s.Start.Before(now.Add(p.MinNotice)):
+		add(availability.ReasonMinNotice, "less than "+p.MinNotice.String()+" notice", nil)
+		inWindow = false
+	case p.MaxAdvance > 0 && s.Start.After(now.Add(p.MaxAdvance)):
+		add(availability.ReasonMaxAdvance, "more than "+p.MaxAdvance.String()+" in advance", nil)
+		inWindow = false
+	}
+	for _, b := range p.bookedWithBuffers() {
+		existing := b.slot
+		if existing.Overlaps(s) {
+			continue
+		}
+		if b.rules.expand(existing).Overlaps(s) || existing.Overlaps(p.EffectiveAvailability(s)) {
+			add(availability.ReasonBuffer, "too close to an existing booking", &existing)
+		}
+	}
+	if err := p.checkLimits(s); err != nil {
+		add(availability.ReasonLimit, err.Error(), nil)
+	}
+	out.Available = inWindow && p.isAvailable(s, p.rules())
+	return out
+}
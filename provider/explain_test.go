@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/availability"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+func TestProviderExplain(t *testing.T) {
+	loc := time.UTC
+	day := time.Now().In(loc).AddDate(0, 0, 7)
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	ws := availability.NewWeeklySchedule(loc)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		ws = ws.SetDay(d, availability.TimeRange{Start: availability.NewTimeOfDay(0, 0, 0), End: availability.EndOfDay})
+	}
+	p := NewProvider("p1", WithWeeklySchedule(ws), WithBuffer(15*time.Minute), WithMinNotice(time.Hour), WithMaxAdvance(30*24*time.Hour))
+	booked := slot.TimeSlot{Start: day.Add(10 * time.Hour), End: day.Add(11 * time.Hour), Location: loc}
+	p.Availability = p.Availability.AddBooking(booked)
+
+	mk := func(start time.Time) slot.TimeSlot {
+		return slot.TimeSlot{Start: start, End: start.Add(30 * time.Minute), Location: loc}
+	}
+	now := time.Now().In(loc)
+	tests := []struct {
+		name      string
+		s         slot.TimeSlot
+		available bool
+		code      availability.ReasonCode
+	}{
+		{"open", mk(day.Add(14 * time.Hour)), true, ""},
+		{"buffer", mk(day.Add(11*time.Hour + 5*time.Minute)), false, availability.ReasonBuffer},
+		{"booked", mk(day.Add(10 * time.Hour)), false, availability.ReasonBooked},
+		{"past", mk(now.Add(-2 * time.Hour)), false, availability.ReasonPast},
+		{"min notice", mk(now.Add(10 * time.Minute)), false, availability.ReasonMinNotice},
+		{"max advance", mk(now.AddDate(0, 2, 0)), false, availability.ReasonMaxAdvance},
+	}
+	for _, tt := range tests {
+		e := p.Explain(tt.s)
+		if e.Available != tt.available {
+			t.Fatalf("%s: expected available=%v, got %+v", tt.name, tt.available, e)
+		}
+		if tt.code != "" && !e.Has(tt.code) {
+			t.Fatalf("%s: expected reason %s, got %+v", tt.name, tt.code, e.Reasons)
+		}
+		_, err := p.Book(tt.s)
+		if (err == nil) != e.Available {
+			t.Fatalf("%s: explanation disagrees with Book (err=%v)", tt.name, err)
+		}
+	}
+	if r := p.Explain(mk(day.Add(11*time.Hour + 5*time.Minute))).Reasons; r[len(r)-1].Slot == nil || !r[len(r)-1].Slot.Equal(booked) {
+		t.Fatalf("buffer reason should reference the booking, got %+v", r)
+	}
+}
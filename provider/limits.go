@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// Limits caps how much a provider may be booked. Days and weeks, which start
+// on Monday, are taken in the provider's location and a booking counts
+// towards the day and week it starts in. Bookings are counted from
+// Provider.Booked, or SeatBookings on capacity providers. Zero fields do
+// not limit.
+type Limits struct {
+	MaxPerDay          int
+	MaxPerWeek         int
+	MaxDurationPerDay  time.Duration
+	MaxDurationPerWeek time.Duration
+	// MaxConsecutive is the longest allowed run of bookings in which each
+	// starts less than MinBreak after the previous one ends.
+	MaxConsecutive int
+	MinBreak       time.Duration
+}
+
+// IsZero reports whether no limit is set.
+func (l Limits) IsZero() bool {
+	return l == Limits{}
+}
+
+// checkLimits reports whether booking s would exceed the provider limits.
+func (p *Provider) checkLimits(s slot.TimeSlot) error {
+	l := p.Limits
+	if l.IsZero() {
+		return nil
+	}
+	loc := p.locationOrUTC()
+	booked := p.bookedSlots()
+	for _, period := range []struct {
+		name  string
+		kind  slot.Period
+		count int
+		total time.Duration
+	}{
+		{"day", slot.PeriodDay, l.MaxPerDay, l.MaxDurationPerDay},
+		{"week", slot.PeriodWeek, l.MaxPerWeek, l.MaxDurationPerWeek},
+	} {
+		if period.count <= 0 && period.total <= 0 {
+			continue
+		}
+		start := slot.PeriodStart(s.Start, period.kind, loc)
+		n, d := 1, s.Duration()
+		for _, b := range booked {
+			if slot.PeriodStart(b.Start, period.kind, loc).Equal(start) {
+				n++
+				d += b.Duration()
+			}
+		}
+		if period.count > 0 && n > period.count {
+			return fmt.Errorf("%w: more than %d bookings per %s", ErrLimitExceeded, period.count, period.name)
+		}
+		if period.total > 0 && d > period.total {
+			return fmt.Errorf("%w: more than %v booked per %s", ErrLimitExceeded, period.total, period.name)
+		}
+	}
+	if l.MaxConsecutive > 0 && consecutiveRun(append(booked, s), s, l.MinBreak) > l.MaxConsecutive {
+		return fmt.Errorf("%w: more than %d bookings without a %v break", ErrLimitExceeded, l.MaxConsecutive, l.MinBreak)
+	}
+	return nil
+}
+
+// consecutiveRun returns the length of the run of bookings containing s in
+// which each starts less than minBreak after the previous one ends. Touching
+// or overlapping bookings always share a run, even when minBreak is zero.
+func consecutiveRun(bookings []slot.TimeSlot, s slot.TimeSlot, minBreak time.Duration) int {
+	run, end := 0, time.Time{}
+	contains := false
+	for _, b := range slot.Sort(bookings) {
+		if run > 0 && b.Start.After(end) && !b.Start.Before(end.Add(minBreak)) {
+			if contains {
+				return run
+			}
+			run = 0
+		}
+		if run == 0 || b.End.After(end) {
+			end = b.End
+		}
+		run++
+		if b.Start.Equal(s.Start) && b.End.Equal(s.End) {
+			contains = true
+		}
+	}
+	return run
+}
+
+// bookedSlots returns the provider's bookings one by one: seats on capacity
+// providers, otherwise the entries of Booked. Bookings only known from
+// Availability.Bookings count once per merged period.
+func (p *Provider) bookedSlots() []slot.TimeSlot {
+	if p.HasCapacity() {
+		return append([]slot.TimeSlot(nil), p.SeatBookings...)
+	}
+	merged := p.Availability.Bookings
+	var out []slot.TimeSlot
+	for _, b := range p.Booked {
+		if merged.Intersect(slot.NewCollection(b)).TotalDuration() == b.Duration() {
+			out = append(out, b)
+		}
+	}
+	return append(out, merged.Subtract(slot.NewCollection(out...)).Slots()...)
+}
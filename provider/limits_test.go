@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/availability"
+	"github.com/Melpic13/timeslot/clock"
+	"github.com/Melpic13/timeslot/query"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+func TestLimits(t *testing.T) {
+	_, monday := dentistFixture()
+	tuesday := monday.AddDate(0, 0, 1)
+	tests := []struct {
+		name    string
+		limits  Limits
+		booked  []slot.TimeSlot
+		refused slot.TimeSlot
+		allowed slot.TimeSlot
+	}{
+		{
+			name:    "per day",
+			limits:  Limits{MaxPerDay: 2},
+			booked:  []slot.TimeSlot{at(monday, 9*time.Hour, time.Hour), at(monday, 10*time.Hour, time.Hour)},
+			refused: at(monday, 14*time.Hour, time.Hour),
+			allowed: at(tuesday, 9*time.Hour, time.Hour),
+		},
+		{
+			name:    "duration per week",
+			limits:  Limits{MaxDurationPerWeek: 3 * time.Hour},
+			booked:  []slot.TimeSlot{at(monday, 9*time.Hour, 2*time.Hour), at(tuesday, 9*time.Hour, time.Hour)},
+			refused: at(monday.AddDate(0, 0, 4), 9*time.Hour, 30*time.Minute),
+			allowed: at(monday.AddDate(0, 0, 7), 9*time.Hour, 2*time.Hour),
+		},
+		{
+			name:    "back to back",
+			limits:  Limits{MaxConsecutive: 3, MinBreak: 30 * time.Minute},
+			booked:  []slot.TimeSlot{at(monday, 9*time.Hour, time.Hour), at(monday, 10*time.Hour, time.Hour), at(monday, 11*time.Hour, 15*time.Minute)},
+			refused: at(monday, 11*time.Hour+30*time.Minute, 30*time.Minute),
+			allowed: at(monday, 11*time.Hour+45*time.Minute, 30*time.Minute),
+		},
+		{
+			name:    "back to back without break",
+			limits:  Limits{MaxConsecutive: 2},
+			booked:  []slot.TimeSlot{at(monday, 9*time.Hour, time.Hour), at(monday, 10*time.Hour, time.Hour)},
+			refused: at(monday, 11*time.Hour, time.Hour),
+			allowed: at(monday, 11*time.Hour+time.Minute, time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := dentistFixture(WithLimits(tt.limits))
+			var err error
+			for _, b := range tt.booked {
+				if p, err = p.Book(b); err != nil {
+					t.Fatalf("book %v: %v", b, err)
+				}
+			}
+			if _, err := p.Book(tt.refused); !errors.Is(err, ErrLimitExceeded) {
+				t.Fatalf("expected %v to exceed the limit, got %v", tt.refused, err)
+			}
+			if p.IsAvailable(tt.refused) {
+				t.Fatalf("expected %v to be unavailable", tt.refused)
+			}
+			if !p.IsAvailable(tt.allowed) {
+				t.Fatalf("expected %v to be available", tt.allowed)
+			}
+			if _, err := p.Book(tt.allowed); err != nil {
+				t.Fatalf("book %v: %v", tt.allowed, err)
+			}
+		})
+	}
+}
+
+func TestLimitsFilterFindSlots(t *testing.T) {
+	p, monday := dentistFixture(WithLimits(Limits{MaxPerDay: 1}))
+	p, err := p.Book(at(monday, 9*time.Hour, time.Hour))
+	if err != nil {
+		t.Fatalf("book: %v", err)
+	}
+	q := query.NewQuery().Duration(time.Hour).Between(monday, monday.AddDate(0, 0, 2)).Build()
+	slots, err := p.FindSlots(q)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if len(slots) == 0 || !slots[0].Start.Equal(monday.AddDate(0, 0, 1).Add(8*time.Hour)) {
+		t.Fatalf("expected Monday to be full, got %v", slots)
+	}
+}
+
+func TestLimitsCountBookedEntries(t *testing.T) {
+	p, monday := dentistFixture(WithLimits(Limits{MaxPerDay: 2}))
+	first, second := at(monday, 9*time.Hour, time.Hour), at(monday, 10*time.Hour, time.Hour)
+	third := at(monday, 14*time.Hour, time.Hour)
+
+	// Touching periods added to Availability alone merge into one booking.
+	merged := p.clone()
+	merged.Availability = merged.Availability.AddBooking(first).AddBooking(second)
+	if !merged.IsAvailable(third) {
+		t.Fatalf("expected the merged period to count once")
+	}
+
+	// Listed in Booked, they count one by one.
+	listed := merged.clone()
+	listed.Booked = []slot.TimeSlot{first, second}
+	if _, err := listed.Book(third); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected a third booking to exceed the limit, got %v", err)
+	}
+	booked, err := p.Book(first)
+	if err == nil {
+		booked, err = booked.Book(second)
+	}
+	if err != nil || len(booked.Booked) != 2 {
+		t.Fatalf("expected Book to list each booking, got %v (%v)", booked.Booked, err)
+	}
+}
+
+func TestLimitsExplained(t *testing.T) {
+	p, monday := dentistFixture(WithLimits(Limits{MaxPerDay: 1}))
+	p, err := p.Book(at(monday, 9*time.Hour, time.Hour))
+	if err != nil {
+		t.Fatalf("book: %v", err)
+	}
+	e := p.Explain(at(monday, 14*time.Hour, time.Hour))
+	if e.Available || !e.Has(availability.ReasonLimit) {
+		t.Fatalf("expected the daily limit to be reported, got %+v", e)
+	}
+	if e := p.Explain(at(monday.AddDate(0, 0, 1), 9*time.Hour, time.Hour)); !e.Available || e.Has(availability.ReasonLimit) {
+		t.Fatalf("expected Tuesday to be within the limit, got %+v", e)
+	}
+}
+
+func TestLimitsUseProviderDays(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	evening := availability.TimeRange{Start: availability.NewTimeOfDay(18, 0, 0), End: availability.EndOfDay}
+	ws := availability.NewWeeklySchedule(ny).SetDay(time.Monday, evening).SetDay(time.Tuesday, evening)
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, ny)
+	p := NewProvider("night", WithTimezone(ny), WithWeeklySchedule(ws), WithClock(clock.Fixed(monday)), WithLimits(Limits{MaxPerDay: 1}))
+
+	// 18:00 and 20:00 in New York fall on different UTC days but the same
+	// local one.
+	early := slot.TimeSlot{Start: monday.Add(18 * time.Hour), End: monday.Add(19 * time.Hour), Location: ny}
+	if p, err = p.Book(early); err != nil {
+		t.Fatalf("book: %v", err)
+	}
+	if _, err := p.Book(early.Shift(2 * time.Hour)); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected Monday to be full, got %v", err)
+	}
+	if _, err := p.Book(early.Shift(24 * time.Hour)); err != nil {
+		t.Fatalf("expected Tuesday evening to be free, got %v", err)
+	}
+}
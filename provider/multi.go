@@ -17,6 +17,9 @@ func FindAny(providers []*Provider, q query.Query) map[string][]slot.TimeSlot {
 	return out
 }
 
+// FindCommon intersects the candidates of every provider. It does not
+// check one provider's buffers against the others' bookings; use Composite
+// to find and book slots needing all of them.
 func FindCommon(providers []*Provider, q query.Query) []slot.TimeSlot {
 	if len(providers) == 0 {
 		return nil
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/Melpic13/timeslot/availability"
+	"github.com/Melpic13/timeslot/clock"
 )
 
 // ProviderOption configures a Provider.
@@ -64,3 +65,45 @@ func WithBlockedDates(dates ...time.Time) ProviderOption {
 		p.Availability = p.Availability.AddBlockedDates(dates...)
 	}
 }
+
+// WithClock sets the clock used for booking-window checks.
+func WithClock(c clock.Clock) ProviderOption {
+	return func(p *Provider) { p.Clock = c }
+}
+
+// WithCapacity sets the number of seats per instant.
+func WithCapacity(seats int) ProviderOption {
+	return func(p *Provider) { p.Capacity = seats }
+}
+
+// WithCapacityRule offers seats instead of the default capacity between
+// start and end.
+func WithCapacityRule(start, end time.Time, seats int) ProviderOption {
+	return func(p *Provider) {
+		p.CapacityRules = append(p.CapacityRules, CapacityRule{Start: start, End: end, Seats: seats})
+		p.refreshCapacity()
+	}
+}
+
+// WithStore makes store the source of truth for the provider's bookings.
+func WithStore(store BookingStore) ProviderOption {
+	return func(p *Provider) { p.Store = store }
+}
+
+// WithLimits caps the provider's daily, weekly and back-to-back load.
+func WithLimits(l Limits) ProviderOption {
+	return func(p *Provider) { p.Limits = l }
+}
+
+// WithService offers svc, replacing any service with the same ID.
+func WithService(svc Service) ProviderOption {
+	return func(p *Provider) {
+		for i, s := range p.Services {
+			if s.ID == svc.ID {
+				p.Services[i] = svc
+				return
+			}
+		}
+		p.Services = append(p.Services, svc)
+	}
+}
@@ -0,0 +1,240 @@
+package provider
+
+import (
+	"github.com/Melpic13/timeslot/internal/errs"
+	"github.com/Melpic13/timeslot/query"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// MetadataMembers is the metadata key Pool.FindSlots uses to list the IDs
+// of the members free for a candidate.
+const MetadataMembers = "members"
+
+// Strategy picks the member of pool that gets s from the members free for
+// it, which are never empty and keep the pool's member order. customer is
+// the one passed to Pool.Book.
+type Strategy func(pool *Pool, s slot.TimeSlot, customer string, free []*Provider) *Provider
+
+// Pool is a group of interchangeable providers booked as one, such as "any
+// available hygienist". Like Provider, its methods return updated copies.
+type Pool struct {
+	ID      string
+	Members []*Provider
+	// Strategy assigns bookings to members; nil uses PriorityOrder.
+	Strategy Strategy
+	// Last is the ID of the member booked most recently and History the
+	// last member booked for each customer.
+	Last    string
+	History map[string]string
+}
+
+func NewPool(id string, strategy Strategy, members ...*Provider) *Pool {
+	return &Pool{ID: id, Members: members, Strategy: strategy, History: map[string]string{}}
+}
+
+// Member returns the member with the given ID.
+func (p *Pool) Member(id string) (*Provider, bool) {
+	for _, m := range p.Members {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// FindSlots returns the distinct candidates free for at least one member,
+// each listing the free members under MetadataMembers. Members failing the
+// query, for example because they lack its service, are skipped.
+func (p *Pool) FindSlots(q query.Query) ([]slot.TimeSlot, error) {
+	memberQuery := q
+	memberQuery.Limit = 0
+	var out []slot.TimeSlot
+	index := map[[2]int64]int{}
+	var lastErr error
+	found := false
+	for _, m := range p.Members {
+		slots, err := m.FindSlots(memberQuery)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		found = true
+		for _, s := range slots {
+			key := [2]int64{s.Start.UnixNano(), s.End.UnixNano()}
+			i, ok := index[key]
+			if !ok {
+				i = len(out)
+				index[key] = i
+				s.Metadata = copyMetadata(s.Metadata)
+				if s.Metadata == nil {
+					s.Metadata = map[string]any{}
+				}
+				s.Metadata[MetadataMembers] = []string(nil)
+				out = append(out, s)
+			}
+			out[i].Metadata[MetadataMembers] = append(out[i].Metadata[MetadataMembers].([]string), m.ID)
+		}
+	}
+	if !found && lastErr != nil {
+		return nil, lastErr
+	}
+	out = query.OptimizeSlots(slot.Sort(out), q)
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out, nil
+}
+
+// IsAvailable reports whether any member is available for s.
+func (p *Pool) IsAvailable(s slot.TimeSlot) bool {
+	for _, m := range p.Members {
+		if m.IsAvailable(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Book assigns s to a free member chosen by the pool strategy and returns
+// the updated pool with the ID of the member booked. customer, which may be
+// empty, is remembered for Sticky. When no member can take s, the first
+// member's error is returned.
+func (p *Pool) Book(s slot.TimeSlot, customer string) (*Pool, string, error) {
+	free, firstErr := p.freeMembers(s)
+	for len(free) > 0 {
+		pick := p.strategy()(p, s, customer, free)
+		if pick == nil {
+			break
+		}
+		booked, err := pick.Book(s)
+		if err != nil {
+			// Store-backed members may have been booked meanwhile.
+			free = without(free, pick)
+			continue
+		}
+		out := p.clone()
+		for i, m := range out.Members {
+			if m == pick {
+				out.Members[i] = booked
+			}
+		}
+		out.Last = pick.ID
+		if customer != "" {
+			out.History[customer] = pick.ID
+		}
+		return out, pick.ID, nil
+	}
+	if firstErr == nil {
+		firstErr = ErrNoAvailability
+	}
+	return nil, "", errs.Wrap("book", s, firstErr)
+}
+
+// freeMembers returns the members that can take s and the error of the
+// first one that cannot.
+func (p *Pool) freeMembers(s slot.TimeSlot) ([]*Provider, error) {
+	var free []*Provider
+	var firstErr error
+	for _, m := range p.Members {
+		cur, err := m.read()
+		if err == nil {
+			err = cur.checkBookable(s, m.rules())
+		}
+		if err == nil {
+			free = append(free, m)
+		} else if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return free, firstErr
+}
+
+func (p *Pool) strategy() Strategy {
+	if p.Strategy == nil {
+		return PriorityOrder
+	}
+	return p.Strategy
+}
+
+func (p *Pool) clone() *Pool {
+	copy := *p
+	copy.Members = append([]*Provider(nil), p.Members...)
+	copy.History = make(map[string]string, len(p.History))
+	for k, v := range p.History {
+		copy.History[k] = v
+	}
+	return &copy
+}
+
+func without(members []*Provider, drop *Provider) []*Provider {
+	var out []*Provider
+	for _, m := range members {
+		if m != drop {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// PriorityOrder picks the first free member in pool order.
+func PriorityOrder(_ *Pool, _ slot.TimeSlot, _ string, free []*Provider) *Provider {
+	return free[0]
+}
+
+// RoundRobin picks the first free member after the one booked last.
+func RoundRobin(pool *Pool, _ slot.TimeSlot, _ string, free []*Provider) *Provider {
+	last := -1
+	for i, m := range pool.Members {
+		if m.ID == pool.Last {
+			last = i
+		}
+	}
+	n := len(pool.Members)
+	for step := 1; step <= n; step++ {
+		candidate := pool.Members[(last+step+n)%n]
+		for _, m := range free {
+			if m == candidate {
+				return m
+			}
+		}
+	}
+	return free[0]
+}
+
+// LeastLoaded picks the free member with the fewest bookings on the day of
+// s, taken in each member's location. Ties go to the earlier member.
+func LeastLoaded(_ *Pool, s slot.TimeSlot, _ string, free []*Provider) *Provider {
+	best, bestLoad := free[0], -1
+	for _, m := range free {
+		loc := m.locationOrUTC()
+		day := slot.PeriodStart(s.Start, slot.PeriodDay, loc)
+		load := 0
+		for _, b := range m.bookedSlots() {
+			if slot.PeriodStart(b.Start, slot.PeriodDay, loc).Equal(day) {
+				load++
+			}
+		}
+		if bestLoad < 0 || load < bestLoad {
+			best, bestLoad = m, load
+		}
+	}
+	return best
+}
+
+// Sticky keeps a customer with the member they were last booked with when
+// that member is free, and otherwise defers to fallback.
+func Sticky(fallback Strategy) Strategy {
+	if fallback == nil {
+		fallback = PriorityOrder
+	}
+	return func(pool *Pool, s slot.TimeSlot, customer string, free []*Provider) *Provider {
+		if id, ok := pool.History[customer]; ok && customer != "" {
+			for _, m := range free {
+				if m.ID == id {
+					return m
+				}
+			}
+		}
+		return fallback(pool, s, customer, free)
+	}
+}
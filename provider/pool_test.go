@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/query"
+)
+
+// hygienists returns three pool members open Monday 09:00-12:00, with the
+// given hours (offsets from Monday midnight) already booked for each.
+func hygienists(t *testing.T, booked ...[]int) ([]*Provider, time.Time) {
+	t.Helper()
+	var members []*Provider
+	var monday time.Time
+	for i, id := range []string{"h1", "h2", "h3"} {
+		var m *Provider
+		m, monday = capacityFixture()
+		m.ID = id
+		if i < len(booked) {
+			for _, h := range booked[i] {
+				var err error
+				if m, err = m.Book(at(monday, time.Duration(h)*time.Hour, time.Hour)); err != nil {
+					t.Fatalf("book %s at %d: %v", id, h, err)
+				}
+			}
+		}
+		members = append(members, m)
+	}
+	return members, monday
+}
+
+func TestPoolStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy Strategy
+		booked   [][]int
+		history  map[string]string
+		hours    []int
+		want     []string
+	}{
+		{"priority order", PriorityOrder, nil, nil, []int{9, 9, 10}, []string{"h1", "h2", "h1"}},
+		{"nil strategy", nil, nil, nil, []int{9, 9}, []string{"h1", "h2"}},
+		{"round robin", RoundRobin, nil, nil, []int{9, 10, 11, 9}, []string{"h1", "h2", "h3", "h2"}},
+		{"least loaded", LeastLoaded, [][]int{{9, 10}, {9}, {10, 11}}, nil, []int{11}, []string{"h2"}},
+		{"sticky", Sticky(RoundRobin), nil, map[string]string{"ada": "h3"}, []int{9, 9, 10}, []string{"h3", "h1", "h1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members, monday := hygienists(t, tt.booked...)
+			pool := NewPool("hygiene", tt.strategy, members...)
+			for k, v := range tt.history {
+				pool.History[k] = v
+			}
+			var got []string
+			for _, h := range tt.hours {
+				next, member, err := pool.Book(at(monday, time.Duration(h)*time.Hour, time.Hour), "ada")
+				if err != nil {
+					t.Fatalf("book %d: %v", h, err)
+				}
+				got = append(got, member)
+				pool = next
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPoolBookRecordsMember(t *testing.T) {
+	members, monday := hygienists(t)
+	pool := NewPool("hygiene", nil, members...)
+	nine := at(monday, 9*time.Hour, time.Hour)
+	booked, member, err := pool.Book(nine, "ada")
+	if err != nil {
+		t.Fatalf("book: %v", err)
+	}
+	m, ok := booked.Member(member)
+	if !ok || m.IsAvailable(nine) || booked.History["ada"] != member || booked.Last != member {
+		t.Fatalf("expected %s to hold the booking", member)
+	}
+	if orig, _ := pool.Member(member); !orig.IsAvailable(nine) || len(pool.History) != 0 {
+		t.Fatalf("original pool should be unchanged")
+	}
+
+	for i := 0; i < 2; i++ {
+		if booked, _, err = booked.Book(nine, ""); err != nil {
+			t.Fatalf("book: %v", err)
+		}
+	}
+	if booked.IsAvailable(nine) {
+		t.Fatalf("expected every member to be busy")
+	}
+	if _, _, err := booked.Book(nine, ""); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected a conflict once every member is busy, got %v", err)
+	}
+}
+
+func TestPoolFindSlots(t *testing.T) {
+	members, monday := hygienists(t, []int{9}, []int{9, 10})
+	pool := NewPool("hygiene", nil, members...)
+	q := query.NewQuery().Duration(time.Hour).Between(monday, monday.AddDate(0, 0, 1)).Build()
+	slots, err := pool.FindSlots(q)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	want := map[int][]string{9: {"h3"}, 10: {"h1", "h3"}, 11: {"h1", "h2", "h3"}}
+	if len(slots) != len(want) {
+		t.Fatalf("expected %d candidates, got %v", len(want), slots)
+	}
+	for _, s := range slots {
+		if got := s.Metadata[MetadataMembers]; !reflect.DeepEqual(got, want[s.Start.Hour()]) {
+			t.Fatalf("%v: expected members %v, got %v", s, want[s.Start.Hour()], got)
+		}
+	}
+	if limited, _ := pool.FindSlots(query.NewQuery().Duration(time.Hour).Between(monday, monday.AddDate(0, 0, 1)).Limit(1).Build()); len(limited) != 1 || limited[0].Start.Hour() != 9 {
+		t.Fatalf("expected the earliest candidate only, got %v", limited)
+	}
+	if _, err := pool.FindSlots(query.Query{}); err == nil {
+		t.Fatalf("expected an invalid query to fail")
+	}
+}
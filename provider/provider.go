@@ -1,10 +1,11 @@
 package provider
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/Melpic13/timeslot/availability"
+	"github.com/Melpic13/timeslot/clock"
+	"github.com/Melpic13/timeslot/internal/errs"
 	"github.com/Melpic13/timeslot/query"
 	"github.com/Melpic13/timeslot/slot"
 )
@@ -18,6 +19,34 @@ type Provider struct {
 	MinNotice    time.Duration
 	MaxAdvance   time.Duration
 	Metadata     map[string]any
+	// Clock supplies the current time for booking-window checks. Nil uses
+	// the system clock.
+	Clock clock.Clock
+	// Capacity is the number of seats per instant; zero or one keeps the
+	// single-booking behaviour. CapacityRules override it for time ranges.
+	Capacity      int
+	CapacityRules []CapacityRule
+	// SeatBookings holds one entry per booked seat on capacity-based
+	// providers. Availability.Bookings then only holds fully booked periods.
+	SeatBookings []slot.TimeSlot
+	// Store, when set, is the source of truth for bookings and records:
+	// every read loads them from it, and every write saves them atomically.
+	// Version is the store version the in-memory bookings were loaded at.
+	Store   BookingStore
+	Version int64
+	// Records holds the booking records created by Hold and CreateBooking.
+	Records []Booking
+	// Services are the offerings bookable through BookService or a query
+	// naming a service. ServiceBookings records which bookings used one.
+	Services        []Service
+	ServiceBookings []ServiceBooking
+	// Limits caps the daily, weekly and back-to-back load of the provider.
+	Limits Limits
+	// Booked lists the slots booked on providers without capacity, one
+	// entry per booking, as Availability.Bookings merges adjacent ones.
+	// Limits count bookings from it; periods added to Availability.Bookings
+	// directly, without an entry here, count once per merged period.
+	Booked []slot.TimeSlot
 }
 
 func NewProvider(id string, opts ...ProviderOption) *Provider {
@@ -38,18 +67,66 @@ func (p *Provider) WithBlockedDates(dates ...time.Time) *Provider {
 	return copy
 }
 
+// FindSlots returns the free candidates matching q. When q names a service,
+// its duration, hours, buffers and booking window apply and candidates
+// carry the service metadata.
 func (p *Provider) FindSlots(q query.Query) ([]slot.TimeSlot, error) {
+	var svc *Service
+	if q.Service != "" {
+		found, err := p.service(q.Service)
+		if err != nil {
+			return nil, err
+		}
+		q.Duration = found.Duration
+		svc = &found
+	}
 	if err := q.Validate(); err != nil {
 		return nil, err
 	}
+	p, err := p.read()
+	if err != nil {
+		return nil, err
+	}
 	free := p.Availability.GetSlots(q.From, q.To)
 	var candidates []slot.TimeSlot
-	for _, s := range free.Slots() {
-		for cur := s.Start; cur.Add(q.Duration).Before(s.End) || cur.Add(q.Duration).Equal(s.End); cur = cur.Add(q.Duration) {
-			candidate := slot.TimeSlot{Start: cur, End: cur.Add(q.Duration), Location: s.Location}
-			if p.passesConstraints(candidate, q.Constraints) {
-				candidates = append(candidates, candidate)
+	it := q.Generator().Iterate(free)
+	for candidate, ok := it.Next(); ok; candidate, ok = it.Next() {
+		if !p.passesConstraints(candidate, q.Constraints) {
+			continue
+		}
+		if svc != nil && (p.checkService(*svc, candidate) != nil || p.checkBookable(candidate, svc.rules()) != nil) {
+			continue
+		}
+		if p.checkLimits(candidate) != nil {
+			continue
+		}
+		// Full periods exclude booked time only, so seats held by buffers
+		// are checked here.
+		if svc == nil && p.HasCapacity() && p.remainingSeats(candidate, p.rules()) <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+	if p.HasCapacity() || svc != nil {
+		for i, c := range candidates {
+			meta := make(map[string]any, len(c.Metadata)+1)
+			for k, v := range c.Metadata {
+				meta[k] = v
 			}
+			if p.HasCapacity() {
+				rules := p.rules()
+				if svc != nil {
+					rules = svc.rules()
+				}
+				meta[MetadataSeatsRemaining] = p.remainingSeats(c, rules)
+			}
+			if svc != nil {
+				for k, v := range svc.Metadata {
+					meta[k] = v
+				}
+				meta[MetadataService] = svc.ID
+			}
+			candidates[i].Metadata = meta
 		}
 	}
 	candidates = query.OptimizeSlots(candidates, q)
@@ -59,7 +136,19 @@ func (p *Provider) FindSlots(q query.Query) ([]slot.TimeSlot, error) {
 	return candidates, nil
 }
 
+// IsAvailable reports whether s could be booked now. A store that cannot be
+// read reports every slot as unavailable.
 func (p *Provider) IsAvailable(s slot.TimeSlot) bool {
+	cur, err := p.read()
+	return err == nil && cur.isAvailable(s, p.rules())
+}
+
+func (p *Provider) isAvailable(s slot.TimeSlot, r bookingRules) bool {
+	return p.isFree(s, r) && p.checkLimits(s) == nil
+}
+
+// isFree is isAvailable without the provider limits.
+func (p *Provider) isFree(s slot.TimeSlot, r bookingRules) bool {
 	from := s.Start.Add(-24 * time.Hour)
 	to := s.End.Add(24 * time.Hour)
 	free := p.Availability.GetSlots(from, to)
@@ -67,8 +156,11 @@ func (p *Provider) IsAvailable(s slot.TimeSlot) bool {
 	if len(overlap) == 0 {
 		return false
 	}
-	for _, existing := range p.Availability.Bookings.Slots() {
-		if p.EffectiveAvailability(existing).Overlaps(s) || existing.Overlaps(p.EffectiveAvailability(s)) {
+	if p.HasCapacity() {
+		return p.remainingSeats(s, r) > 0
+	}
+	for _, b := range p.bookedWithBuffers() {
+		if b.rules.expand(b.slot).Overlaps(s) || b.slot.Overlaps(r.expand(s)) {
 			return false
 		}
 	}
@@ -76,46 +168,168 @@ func (p *Provider) IsAvailable(s slot.TimeSlot) bool {
 }
 
 func (p *Provider) Book(s slot.TimeSlot) (*Provider, error) {
-	now := time.Now().In(p.locationOrUTC())
-	if s.Start.Before(now) {
-		return nil, fmt.Errorf("provider: cannot book in the past")
+	return p.write(func(cur *Provider) (*Provider, error) {
+		return cur.book(s, cur.rules())
+	})
+}
+
+func (p *Provider) book(s slot.TimeSlot, r bookingRules) (*Provider, error) {
+	if err := p.checkBookable(s, r); err != nil {
+		return nil, errs.Wrap("book", s, err)
 	}
-	if p.MinNotice > 0 && s.Start.Before(now.Add(p.MinNotice)) {
-		return nil, fmt.Errorf("provider: insufficient notice")
+	copy := p.clone()
+	copy.addBooking(s)
+	return copy, nil
+}
+
+func (p *Provider) checkBookable(s slot.TimeSlot, r bookingRules) error {
+	if err := r.checkWindow(s, p.Now()); err != nil {
+		return err
 	}
-	if p.MaxAdvance > 0 && s.Start.After(now.Add(p.MaxAdvance)) {
-		return nil, fmt.Errorf("provider: booking too far in advance")
+	if p.isFree(s, r) {
+		return p.checkLimits(s)
 	}
-	if !p.IsAvailable(s) {
-		return nil, fmt.Errorf("provider: slot not available")
+	schedule := p.Availability
+	schedule.Bookings = slot.NewCollection()
+	if len(schedule.GetSlots(s.Start.Add(-24*time.Hour), s.End.Add(24*time.Hour)).FindOverlaps(s)) == 0 {
+		return ErrNoAvailability
 	}
-	copy := p.clone()
-	copy.Availability = copy.Availability.AddBooking(s)
-	return copy, nil
+	return ErrConflict
+}
+
+func (p *Provider) addBooking(s slot.TimeSlot) {
+	if p.HasCapacity() {
+		p.SeatBookings = append(p.SeatBookings, s)
+		p.refreshCapacity()
+		return
+	}
+	p.Availability = p.Availability.AddBooking(s)
+	p.Booked = append(p.Booked, s)
+}
+
+// Now returns the current time from the provider's clock in its location.
+func (p *Provider) Now() time.Time {
+	return clock.Or(p.Clock).Now().In(p.locationOrUTC())
+}
+
+// CheckBookingWindow reports whether s may be booked at now given the
+// past, MinNotice and MaxAdvance rules.
+func (p *Provider) CheckBookingWindow(s slot.TimeSlot, now time.Time) error {
+	return p.rules().checkWindow(s, now)
+}
+
+// Reschedule moves the booking old to next. next is validated as if old
+// were already gone, so a booking may move within its own buffer, and
+// under the rules of old's service if it was booked for one. Either both
+// changes are applied to the returned copy or, on error, neither.
+func (p *Provider) Reschedule(old, next slot.TimeSlot) (*Provider, error) {
+	return p.write(func(cur *Provider) (*Provider, error) {
+		return cur.reschedule(old, next)
+	})
+}
+
+func (p *Provider) reschedule(old, next slot.TimeSlot) (*Provider, error) {
+	id, rules := p.rulesFor(old)
+	moved := p.clone()
+	if !moved.removeBooking(old) {
+		return nil, errs.Wrap("reschedule", old, ErrBookingNotFound)
+	}
+	err := moved.checkBookable(next, rules)
+	if svc, ok := p.Service(id); ok && err == nil {
+		err = p.checkService(svc, next)
+	}
+	if err != nil {
+		return nil, errs.Wrap("reschedule", next, err)
+	}
+	moved.addBooking(next)
+	if id != "" {
+		moved.ServiceBookings = append(moved.ServiceBookings, ServiceBooking{Slot: next, Service: id})
+	}
+	for i, b := range moved.Records {
+		if (b.Status == StatusHeld || b.Status == StatusConfirmed) && b.Slot.Start.Equal(old.Start) && b.Slot.End.Equal(old.End) {
+			moved.Records[i].Slot = next
+			break
+		}
+	}
+	return moved, nil
 }
 
 func (p *Provider) CancelBooking(s slot.TimeSlot) (*Provider, error) {
+	return p.write(func(cur *Provider) (*Provider, error) {
+		return cur.cancel(s)
+	})
+}
+
+func (p *Provider) cancel(s slot.TimeSlot) (*Provider, error) {
 	copy := p.clone()
-	before := copy.Availability.Bookings.Len()
-	copy.Availability = copy.Availability.RemoveBooking(s)
-	if before == copy.Availability.Bookings.Len() {
-		return nil, fmt.Errorf("provider: booking not found")
+	if !copy.removeBooking(s) {
+		return nil, errs.Wrap("cancel", s, ErrBookingNotFound)
 	}
 	return copy, nil
 }
 
+func (p *Provider) removeBooking(s slot.TimeSlot) bool {
+	if p.HasCapacity() {
+		for i, seat := range p.SeatBookings {
+			if seat.Start.Equal(s.Start) && seat.End.Equal(s.End) {
+				p.SeatBookings = append(p.SeatBookings[:i], p.SeatBookings[i+1:]...)
+				p.refreshCapacity()
+				p.dropServiceBooking(s)
+				return true
+			}
+		}
+		return false
+	}
+	for i, b := range p.Booked {
+		if b.Start.Equal(s.Start) && b.End.Equal(s.End) {
+			p.Booked = append(p.Booked[:i], p.Booked[i+1:]...)
+			p.Availability = p.Availability.RemoveBooking(s)
+			p.dropServiceBooking(s)
+			return true
+		}
+	}
+	// Bookings added to Availability directly are only known merged, so any
+	// covered part of them may be removed, but never part of a booking.
+	for _, b := range p.Booked {
+		if b.Overlaps(s) {
+			return false
+		}
+	}
+	if p.Availability.Bookings.Intersect(slot.NewCollection(s)).TotalDuration() != s.Duration() {
+		return false
+	}
+	p.Availability = p.Availability.RemoveBooking(s)
+	return true
+}
+
+// GetBookings returns the bookings overlapping [from, to), or nil when the
+// store cannot be read. LoadBookings reports that error.
 func (p *Provider) GetBookings(from, to time.Time) []slot.TimeSlot {
+	out, _ := p.LoadBookings(from, to)
+	return out
+}
+
+// LoadBookings is GetBookings returning the store error, if any.
+func (p *Provider) LoadBookings(from, to time.Time) ([]slot.TimeSlot, error) {
+	p, err := p.read()
+	if err != nil {
+		return nil, err
+	}
+	if p.HasCapacity() {
+		var out []slot.TimeSlot
+		for _, seat := range p.SeatBookings {
+			if seat.Start.Before(to) && seat.End.After(from) {
+				out = append(out, seat)
+			}
+		}
+		return out, nil
+	}
 	window := slot.NewCollection(slot.TimeSlot{Start: from, End: to, Location: p.locationOrUTC()})
-	return p.Availability.Bookings.Intersect(window).Slots()
+	return p.Availability.Bookings.Intersect(window).Slots(), nil
 }
 
 func (p *Provider) EffectiveAvailability(s slot.TimeSlot) slot.TimeSlot {
-	return slot.TimeSlot{
-		Start:    s.Start.Add(-p.BufferBefore),
-		End:      s.End.Add(p.BufferAfter),
-		Location: s.Location,
-		Metadata: s.Metadata,
-	}
+	return p.rules().expand(s)
 }
 
 func (p *Provider) passesConstraints(candidate slot.TimeSlot, constraints []query.Constraint) bool {
@@ -134,6 +348,12 @@ func (p *Provider) clone() *Provider {
 	}
 	copy := *p
 	copy.Metadata = meta
+	copy.CapacityRules = append([]CapacityRule(nil), p.CapacityRules...)
+	copy.SeatBookings = append([]slot.TimeSlot(nil), p.SeatBookings...)
+	copy.Records = append([]Booking(nil), p.Records...)
+	copy.Services = append([]Service(nil), p.Services...)
+	copy.ServiceBookings = append([]ServiceBooking(nil), p.ServiceBookings...)
+	copy.Booked = append([]slot.TimeSlot(nil), p.Booked...)
 	return &copy
 }
 
@@ -1,11 +1,14 @@
 package provider
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Melpic13/timeslot/availability"
+	"github.com/Melpic13/timeslot/clock"
 	"github.com/Melpic13/timeslot/query"
+	"github.com/Melpic13/timeslot/slot"
 )
 
 func TestProviderFindSlots(t *testing.T) {
@@ -22,3 +25,44 @@ func TestProviderFindSlots(t *testing.T) {
 		t.Fatalf("expected 2, got %d", len(slots))
 	}
 }
+
+func TestProviderFindSlotsWithStep(t *testing.T) {
+	ws := availability.NewWeeklySchedule(time.UTC).SetDay(time.Monday, availability.TimeRange{Start: availability.NewTimeOfDay(9, 0, 0), End: availability.NewTimeOfDay(11, 30, 0)})
+	p := NewProvider("p1", WithWeeklySchedule(ws))
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	q := query.NewQuery().Duration(time.Hour).Step(30*time.Minute).Between(from, from.Add(24*time.Hour)).Build()
+	slots, err := p.FindSlots(q)
+	if err != nil {
+		t.Fatalf("find slots: %v", err)
+	}
+	if len(slots) != 4 {
+		t.Fatalf("expected 4 sliding candidates, got %d", len(slots))
+	}
+}
+
+func TestProviderBookWithClock(t *testing.T) {
+	now := time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+	clk := clock.NewManual(now)
+	ws := availability.NewWeeklySchedule(time.UTC).SetDay(time.Monday, availability.TimeRange{Start: availability.NewTimeOfDay(9, 0, 0), End: availability.NewTimeOfDay(17, 0, 0)})
+	p := NewProvider("p", WithWeeklySchedule(ws), WithClock(clk), WithMinNotice(2*time.Hour), WithMaxAdvance(14*24*time.Hour))
+	s := slot.TimeSlot{Start: now.Add(3 * time.Hour), End: now.Add(4 * time.Hour), Location: time.UTC}
+
+	if !p.Now().Equal(now) {
+		t.Fatalf("provider should use its clock, got %v", p.Now())
+	}
+	if _, err := p.Book(s); err != nil {
+		t.Fatalf("expected booking at fixed time to succeed: %v", err)
+	}
+	clk.Advance(2 * time.Hour)
+	if _, err := p.Book(s); err == nil || !strings.Contains(err.Error(), "notice") {
+		t.Fatalf("expected insufficient notice after advancing the clock, got %v", err)
+	}
+	clk.Set(now.Add(-30 * 24 * time.Hour))
+	if _, err := p.Book(s); err == nil || !strings.Contains(err.Error(), "advance") {
+		t.Fatalf("expected too-far-in-advance error, got %v", err)
+	}
+	clk.Advance(60 * 24 * time.Hour)
+	if _, err := p.Book(s); err == nil || !strings.Contains(err.Error(), "past") {
+		t.Fatalf("expected past error, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/slot"
+)
+
+func TestRescheduleWithinOwnBuffer(t *testing.T) {
+	p, monday := capacityFixture(WithBuffer(30 * time.Minute))
+	old := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+	p, err := p.Book(old)
+	if err != nil {
+		t.Fatalf("book: %v", err)
+	}
+	if _, err := p.Book(old.Shift(15 * time.Minute)); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected a plain booking to conflict with the buffer, got %v", err)
+	}
+
+	next := old.Shift(15 * time.Minute)
+	moved, err := p.Reschedule(old, next)
+	if err != nil {
+		t.Fatalf("reschedule: %v", err)
+	}
+	got := moved.GetBookings(monday, monday.Add(24*time.Hour))
+	if len(got) != 1 || !got[0].Start.Equal(next.Start) {
+		t.Fatalf("expected only the moved booking, got %v", got)
+	}
+	if orig := p.GetBookings(monday, monday.Add(24*time.Hour)); len(orig) != 1 || !orig[0].Start.Equal(old.Start) {
+		t.Fatalf("original provider should be unchanged, got %v", orig)
+	}
+}
+
+func TestRescheduleFailureKeepsOriginal(t *testing.T) {
+	base, monday := capacityFixture(WithMinNotice(2 * time.Hour))
+	hour := func(h int) slot.TimeSlot {
+		return slot.TimeSlot{Start: monday.Add(time.Duration(h) * time.Hour), End: monday.Add(time.Duration(h+1) * time.Hour), Location: time.UTC}
+	}
+	p, err := base.Book(hour(9))
+	if err == nil {
+		p, err = p.Book(hour(11))
+	}
+	if err != nil {
+		t.Fatalf("book: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		old, new slot.TimeSlot
+		want     error
+	}{
+		{"not booked", hour(10), hour(10), ErrBookingNotFound},
+		{"conflict", hour(9), hour(11), ErrConflict},
+		{"outside hours", hour(9), hour(13), ErrNoAvailability},
+		{"past", hour(9), hour(9).Shift(-24 * time.Hour), ErrPastTime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := p.Reschedule(tt.old, tt.new); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			if got := p.GetBookings(monday, monday.Add(24*time.Hour)); len(got) != 2 {
+				t.Fatalf("expected bookings to be untouched, got %v", got)
+			}
+		})
+	}
+}
+
+func TestRescheduleAdjacentBooking(t *testing.T) {
+	p, monday := capacityFixture()
+	first := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+	p, err := p.Book(first)
+	if err == nil {
+		p, err = p.Book(first.Shift(time.Hour))
+	}
+	if err != nil {
+		t.Fatalf("book: %v", err)
+	}
+	moved, err := p.Reschedule(first.Shift(time.Hour), first.Shift(2*time.Hour))
+	if err != nil {
+		t.Fatalf("reschedule: %v", err)
+	}
+	if got := moved.GetBookings(monday, monday.Add(24*time.Hour)); len(got) != 2 || !got[1].Start.Equal(monday.Add(11*time.Hour)) {
+		t.Fatalf("unexpected bookings %v", got)
+	}
+}
+
+func TestRescheduleUpdatesRecordAndStore(t *testing.T) {
+	store := NewMemoryStore()
+	p, monday := capacityFixture(WithStore(store))
+	old := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+	p, b, err := p.CreateBooking(old, map[string]any{"name": "ada"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	next := old.Shift(time.Hour)
+	moved, err := p.Reschedule(old, next)
+	if err != nil {
+		t.Fatalf("reschedule: %v", err)
+	}
+	if rec, ok := moved.Booking(b.ID); !ok || !rec.Slot.Start.Equal(next.Start) {
+		t.Fatalf("expected booking record to move, got %+v", rec)
+	}
+	state, _ := store.Load(p.ID)
+	if len(state.Bookings) != 1 || !state.Bookings[0].Start.Equal(next.Start) || !state.Records[0].Slot.Start.Equal(next.Start) || state.Version != moved.Version {
+		t.Fatalf("unexpected store state %+v", state)
+	}
+}
+
+func TestRescheduleNeedsWholeBooking(t *testing.T) {
+	p, monday := capacityFixture()
+	booked := at(monday, 9*time.Hour, time.Hour)
+	p, err := p.Book(booked)
+	if err != nil {
+		t.Fatalf("book: %v", err)
+	}
+	part := at(monday, 9*time.Hour+15*time.Minute, 30*time.Minute)
+	if _, err := p.Reschedule(part, part.Shift(2*time.Hour)); !errors.Is(err, ErrBookingNotFound) {
+		t.Fatalf("expected part of a booking to be unknown, got %v", err)
+	}
+	if _, err := p.CancelBooking(part); !errors.Is(err, ErrBookingNotFound) {
+		t.Fatalf("expected part of a booking to be unknown, got %v", err)
+	}
+
+	// Bookings only known to Availability can still be cut.
+	p.Availability = p.Availability.AddBooking(at(monday, 11*time.Hour, time.Hour))
+	cut, err := p.CancelBooking(at(monday, 11*time.Hour, 30*time.Minute))
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if got := cut.GetBookings(monday, monday.AddDate(0, 0, 1)); len(got) != 2 || !got[1].Start.Equal(monday.Add(11*time.Hour+30*time.Minute)) {
+		t.Fatalf("unexpected bookings %v", got)
+	}
+}
@@ -0,0 +1,218 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Melpic13/timeslot/availability"
+	"github.com/Melpic13/timeslot/internal/errs"
+	"github.com/Melpic13/timeslot/internal/timeutil"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// MetadataService is the metadata key FindSlots uses to tag candidates with
+// the service they were searched for. The service's own metadata, such as
+// its price, is copied alongside.
+const MetadataService = "service"
+
+// Service is a bookable offering with its own duration, buffers and booking
+// window, which replace the provider's for bookings of the service. When
+// Weekdays or Hours are set, bookings must start on one of the weekdays and
+// fit inside one of the ranges.
+type Service struct {
+	ID           string
+	Name         string
+	Duration     time.Duration
+	BufferBefore time.Duration
+	BufferAfter  time.Duration
+	MinNotice    time.Duration
+	MaxAdvance   time.Duration
+	Weekdays     []time.Weekday
+	Hours        []availability.TimeRange
+	Metadata     map[string]any
+}
+
+// ServiceBooking records the service a booked slot was made for, so its
+// buffers keep applying to later bookings.
+type ServiceBooking struct {
+	Slot    slot.TimeSlot
+	Service string
+}
+
+func (s Service) Validate() error {
+	if s.Duration <= 0 {
+		return fmt.Errorf("%w: service %q duration %v", errs.ErrInvalidDuration, s.ID, s.Duration)
+	}
+	if s.BufferBefore < 0 || s.BufferAfter < 0 || s.MinNotice < 0 || s.MaxAdvance < 0 {
+		return fmt.Errorf("%w: service %q has a negative buffer or notice", errs.ErrInvalidDuration, s.ID)
+	}
+	for _, r := range s.Hours {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Service returns the service registered under id.
+func (p *Provider) Service(id string) (Service, bool) {
+	for _, s := range p.Services {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Service{}, false
+}
+
+// BookService books s for the service id, applying the service's duration,
+// hours, buffers and booking window instead of the provider's.
+func (p *Provider) BookService(id string, s slot.TimeSlot) (*Provider, error) {
+	svc, err := p.service(id)
+	if err == nil {
+		err = p.checkService(svc, s)
+	}
+	if err != nil {
+		return nil, errs.Wrap("book", s, err)
+	}
+	return p.write(func(cur *Provider) (*Provider, error) {
+		booked, err := cur.book(s, svc.rules())
+		if err != nil {
+			return nil, err
+		}
+		booked.ServiceBookings = append(booked.ServiceBookings, ServiceBooking{Slot: s, Service: id})
+		return booked, nil
+	})
+}
+
+func (p *Provider) service(id string) (Service, error) {
+	svc, ok := p.Service(id)
+	if !ok {
+		return Service{}, fmt.Errorf("%w %q", ErrUnknownService, id)
+	}
+	return svc, svc.Validate()
+}
+
+// checkService reports whether s matches the duration, weekdays and hours
+// of svc.
+func (p *Provider) checkService(svc Service, s slot.TimeSlot) error {
+	if s.Duration() != svc.Duration {
+		return fmt.Errorf("%w: service %q takes %v", errs.ErrInvalidDuration, svc.ID, svc.Duration)
+	}
+	loc := p.locationOrUTC()
+	start := s.Start.In(loc)
+	if len(svc.Weekdays) > 0 && !containsWeekday(svc.Weekdays, start.Weekday()) {
+		return errs.ErrNoAvailability
+	}
+	if len(svc.Hours) == 0 {
+		return nil
+	}
+	// Overnight hours that began the previous day may still cover s.
+	for _, day := range []time.Time{start, timeutil.AddDays(start, -1, loc)} {
+		for _, r := range svc.Hours {
+			w := r.SlotOn(day, loc)
+			if !s.Start.Before(w.Start) && !s.End.After(w.End) {
+				return nil
+			}
+		}
+	}
+	return errs.ErrNoAvailability
+}
+
+func containsWeekday(days []time.Weekday, d time.Weekday) bool {
+	for _, day := range days {
+		if day == d {
+			return true
+		}
+	}
+	return false
+}
+
+// bookingRules are the buffers and booking window applied to one booking.
+type bookingRules struct {
+	before, after         time.Duration
+	minNotice, maxAdvance time.Duration
+}
+
+func (p *Provider) rules() bookingRules {
+	return bookingRules{before: p.BufferBefore, after: p.BufferAfter, minNotice: p.MinNotice, maxAdvance: p.MaxAdvance}
+}
+
+func (s Service) rules() bookingRules {
+	return bookingRules{before: s.BufferBefore, after: s.BufferAfter, minNotice: s.MinNotice, maxAdvance: s.MaxAdvance}
+}
+
+func (r bookingRules) expand(s slot.TimeSlot) slot.TimeSlot {
+	return slot.TimeSlot{
+		Start:    s.Start.Add(-r.before),
+		End:      s.End.Add(r.after),
+		Location: s.Location,
+		Metadata: s.Metadata,
+	}
+}
+
+func (r bookingRules) checkWindow(s slot.TimeSlot, now time.Time) error {
+	if s.Start.Before(now) {
+		return ErrPastTime
+	}
+	if r.minNotice > 0 && s.Start.Before(now.Add(r.minNotice)) {
+		return ErrInsufficientNotice
+	}
+	if r.maxAdvance > 0 && s.Start.After(now.Add(r.maxAdvance)) {
+		return ErrTooFarAdvance
+	}
+	return nil
+}
+
+// rulesFor returns the rules of the service booked as s, or the provider's
+// when s was not booked for a known service.
+func (p *Provider) rulesFor(s slot.TimeSlot) (string, bookingRules) {
+	id := p.serviceOf(s)
+	if svc, ok := p.Service(id); ok {
+		return id, svc.rules()
+	}
+	return id, p.rules()
+}
+
+func (p *Provider) serviceOf(s slot.TimeSlot) string {
+	for _, sb := range p.ServiceBookings {
+		if sb.Slot.Start.Equal(s.Start) && sb.Slot.End.Equal(s.End) {
+			return sb.Service
+		}
+	}
+	return ""
+}
+
+type bufferedBooking struct {
+	slot  slot.TimeSlot
+	rules bookingRules
+}
+
+// bookedWithBuffers returns the booked periods with the rules they were
+// booked under. Service bookings no longer present in the bookings, for
+// example after a store sync, are ignored.
+func (p *Provider) bookedWithBuffers() []bufferedBooking {
+	booked := p.Availability.Bookings
+	var out []bufferedBooking
+	var services []slot.TimeSlot
+	for _, sb := range p.ServiceBookings {
+		if booked.Intersect(slot.NewCollection(sb.Slot)).TotalDuration() != sb.Slot.Duration() {
+			continue
+		}
+		_, r := p.rulesFor(sb.Slot)
+		out = append(out, bufferedBooking{slot: sb.Slot, rules: r})
+		services = append(services, sb.Slot)
+	}
+	for _, s := range booked.Subtract(slot.NewCollection(services...)).Slots() {
+		out = append(out, bufferedBooking{slot: s, rules: p.rules()})
+	}
+	return out
+}
+
+func (p *Provider) dropServiceBooking(s slot.TimeSlot) {
+	for i, sb := range p.ServiceBookings {
+		if sb.Slot.Start.Equal(s.Start) && sb.Slot.End.Equal(s.End) {
+			p.ServiceBookings = append(p.ServiceBookings[:i], p.ServiceBookings[i+1:]...)
+			return
+		}
+	}
+}
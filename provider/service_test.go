@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/availability"
+	"github.com/Melpic13/timeslot/clock"
+	"github.com/Melpic13/timeslot/internal/errs"
+	"github.com/Melpic13/timeslot/query"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// dentistFixture opens 08:00-18:00 on weekdays with the clock at Monday
+// 2025-01-06 08:00. Surgeries run on Tuesdays and Thursdays mornings only.
+func dentistFixture(opts ...ProviderOption) (*Provider, time.Time) {
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	day := availability.TimeRange{Start: availability.NewTimeOfDay(8, 0, 0), End: availability.NewTimeOfDay(18, 0, 0)}
+	ws := availability.NewWeeklySchedule(time.UTC)
+	for d := time.Monday; d <= time.Friday; d++ {
+		ws = ws.SetDay(d, day)
+	}
+	base := []ProviderOption{
+		WithWeeklySchedule(ws),
+		WithClock(clock.Fixed(monday.Add(8 * time.Hour))),
+		WithService(Service{ID: "checkup", Duration: 15 * time.Minute, Metadata: map[string]any{"price": 40}}),
+		WithService(Service{
+			ID:          "surgery",
+			Duration:    90 * time.Minute,
+			BufferAfter: 30 * time.Minute,
+			MinNotice:   48 * time.Hour,
+			Weekdays:    []time.Weekday{time.Tuesday, time.Thursday},
+			Hours:       []availability.TimeRange{{Start: availability.NewTimeOfDay(8, 0, 0), End: availability.NewTimeOfDay(12, 0, 0)}},
+			Metadata:    map[string]any{"price": 900},
+		}),
+	}
+	return capacityFixture(append(base, opts...)...)
+}
+
+func at(day time.Time, offset, d time.Duration) slot.TimeSlot {
+	return slot.TimeSlot{Start: day.Add(offset), End: day.Add(offset + d), Location: time.UTC}
+}
+
+func TestBookServiceAppliesServiceRules(t *testing.T) {
+	p, monday := dentistFixture(WithBuffer(time.Hour))
+	thursday := monday.AddDate(0, 0, 3)
+	surgery := at(thursday, 9*time.Hour, 90*time.Minute)
+
+	failures := []struct {
+		name    string
+		service string
+		s       slot.TimeSlot
+		want    error
+	}{
+		{"unknown service", "whitening", surgery, ErrUnknownService},
+		{"wrong duration", "surgery", at(thursday, 9*time.Hour, time.Hour), errs.ErrInvalidDuration},
+		{"weekday", "surgery", at(monday.AddDate(0, 0, 2), 9*time.Hour, 90*time.Minute), ErrNoAvailability},
+		{"hours", "surgery", at(thursday, 11*time.Hour, 90*time.Minute), ErrNoAvailability},
+		{"notice", "surgery", at(monday.AddDate(0, 0, 1), 9*time.Hour, 90*time.Minute), ErrInsufficientNotice},
+	}
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := p.BookService(tt.service, tt.s); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	p, err := p.BookService("surgery", surgery)
+	if err != nil {
+		t.Fatalf("surgery: %v", err)
+	}
+	// A checkup has no buffer of its own, so only the surgery cleanup counts,
+	// not the provider's one-hour buffer.
+	if _, err := p.BookService("checkup", at(thursday, 10*time.Hour+45*time.Minute, 15*time.Minute)); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected the surgery cleanup to block a checkup, got %v", err)
+	}
+	if p, err = p.BookService("checkup", at(thursday, 8*time.Hour+45*time.Minute, 15*time.Minute)); err != nil {
+		t.Fatalf("checkup before surgery: %v", err)
+	}
+	if p, err = p.BookService("checkup", at(thursday, 11*time.Hour, 15*time.Minute)); err != nil {
+		t.Fatalf("checkup after cleanup: %v", err)
+	}
+	// Plain bookings still use the provider buffer.
+	if _, err := p.Book(at(thursday, 12*time.Hour, 15*time.Minute)); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected the provider buffer to apply, got %v", err)
+	}
+}
+
+func TestFindSlotsForService(t *testing.T) {
+	p, monday := dentistFixture()
+	q := query.NewQuery().Service("surgery").Between(monday, monday.AddDate(0, 0, 5)).Build()
+	slots, err := p.FindSlots(q)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	thursday := monday.AddDate(0, 0, 3)
+	want := []slot.TimeSlot{at(thursday, 8*time.Hour, 90*time.Minute), at(thursday, 9*time.Hour+30*time.Minute, 90*time.Minute)}
+	if len(slots) != len(want) {
+		t.Fatalf("expected %d surgery slots, got %v", len(want), slots)
+	}
+	for i, s := range slots {
+		if !s.Start.Equal(want[i].Start) || !s.End.Equal(want[i].End) {
+			t.Fatalf("slot %d: expected %v, got %v", i, want[i], s)
+		}
+		if s.Metadata[MetadataService] != "surgery" || s.Metadata["price"] != 900 {
+			t.Fatalf("expected service metadata, got %v", s.Metadata)
+		}
+	}
+
+	checkups, err := p.FindSlots(query.NewQuery().Service("checkup").Between(monday, monday.AddDate(0, 0, 1)).Limit(1).Build())
+	if err != nil || len(checkups) != 1 || !checkups[0].Start.Equal(monday.Add(8*time.Hour)) || checkups[0].Duration() != 15*time.Minute {
+		t.Fatalf("expected a Monday 08:00 checkup, got %v (%v)", checkups, err)
+	}
+	if _, err := p.FindSlots(query.NewQuery().Service("whitening").Between(monday, thursday).Build()); !errors.Is(err, ErrUnknownService) {
+		t.Fatalf("expected unknown service, got %v", err)
+	}
+}
+
+func TestRescheduleKeepsServiceRules(t *testing.T) {
+	p, monday := dentistFixture()
+	thursday := monday.AddDate(0, 0, 3)
+	surgery := at(thursday, 9*time.Hour, 90*time.Minute)
+	p, err := p.BookService("surgery", surgery)
+	if err != nil {
+		t.Fatalf("surgery: %v", err)
+	}
+	if _, err := p.Reschedule(surgery, surgery.Shift(24*time.Hour)); !errors.Is(err, ErrNoAvailability) {
+		t.Fatalf("expected Friday to be refused for surgery, got %v", err)
+	}
+	moved, err := p.Reschedule(surgery, surgery.Shift(15*time.Minute))
+	if err != nil {
+		t.Fatalf("reschedule: %v", err)
+	}
+	if _, err := moved.BookService("checkup", at(thursday, 11*time.Hour, 15*time.Minute)); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected the moved surgery to keep its cleanup, got %v", err)
+	}
+	if cancelled, err := moved.CancelBooking(surgery.Shift(15 * time.Minute)); err != nil || len(cancelled.ServiceBookings) != 0 {
+		t.Fatalf("expected cancelling to drop the service booking, got %v (%v)", cancelled, err)
+	}
+}
+
+func TestBookServicePersistsService(t *testing.T) {
+	store := NewMemoryStore()
+	p, monday := dentistFixture(WithStore(store))
+	thursday := monday.AddDate(0, 0, 3)
+	surgery := at(thursday, 9*time.Hour, 90*time.Minute)
+	if _, err := p.BookService("surgery", surgery); err != nil {
+		t.Fatalf("surgery: %v", err)
+	}
+	if state, _ := store.Load(p.ID); len(state.Services) != 1 || state.Services[0].Service != "surgery" {
+		t.Fatalf("expected the service to be stored with the booking, got %v", state.Services)
+	}
+	// p itself is stale; the surgery's cleanup must still apply from the store.
+	if _, err := p.BookService("checkup", at(thursday, 10*time.Hour+45*time.Minute, 15*time.Minute)); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected the stored surgery to keep its cleanup, got %v", err)
+	}
+	if _, err := p.CancelBooking(surgery); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if state, _ := store.Load(p.ID); len(state.Bookings) != 0 || len(state.Services) != 0 {
+		t.Fatalf("expected cancelling to drop the stored service, got %v", state)
+	}
+}
@@ -0,0 +1,244 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Melpic13/timeslot/slot"
+)
+
+// StoreState is what a BookingStore keeps for one provider: the booked
+// slots, one entry per booking, the services bookings were made for, and
+// the booking records made through Hold and CreateBooking. Version counts
+// the saves.
+type StoreState struct {
+	Version  int64            `json:"version"`
+	Bookings []slot.TimeSlot  `json:"bookings"`
+	Services []ServiceBooking `json:"services,omitempty"`
+	Records  []Booking        `json:"records,omitempty"`
+}
+
+// BookingStore persists the bookings of each provider. Save replaces the
+// state of a provider and increments its version; saves whose expected
+// version is stale fail with ErrVersionConflict, which makes
+// check-then-write atomic for callers that reload and re-check after a
+// conflict.
+type BookingStore interface {
+	Load(providerID string) (StoreState, error)
+	Save(providerID string, state StoreState, expected int64) (int64, error)
+}
+
+// maxStoreAttempts bounds how often writes reload and re-check after
+// losing a race to another writer.
+const maxStoreAttempts = 5
+
+// Sync returns a copy whose bookings, services, records and Version are those
+// currently in the store. Without a store it returns a plain copy.
+func (p *Provider) Sync() (*Provider, error) {
+	copy := p.clone()
+	if p.Store == nil {
+		return copy, nil
+	}
+	state, err := p.Store.Load(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	if copy.HasCapacity() {
+		copy.SeatBookings = state.Bookings
+		copy.refreshCapacity()
+	} else {
+		copy.Availability.Bookings = slot.NewCollection(state.Bookings...)
+		copy.Booked = state.Bookings
+	}
+	copy.ServiceBookings = state.Services
+	copy.Records = state.Records
+	copy.Version = state.Version
+	return copy, nil
+}
+
+// read returns the state reads should see: the bookings currently in the
+// store on store-backed providers, with lapsed holds released.
+func (p *Provider) read() (*Provider, error) {
+	if p.Store == nil {
+		return p.current(), nil
+	}
+	synced, err := p.Sync()
+	if err != nil {
+		return nil, err
+	}
+	return synced.current(), nil
+}
+
+// write applies change to the current state, with lapsed holds released.
+// On store-backed providers that is the state in the store, and the result
+// is saved back, retrying from a fresh load when another writer saved
+// first.
+func (p *Provider) write(change func(cur *Provider) (*Provider, error)) (*Provider, error) {
+	if p.Store == nil {
+		return change(p.current())
+	}
+	for attempt := 0; attempt < maxStoreAttempts; attempt++ {
+		cur, err := p.read()
+		if err != nil {
+			return nil, err
+		}
+		next, err := change(cur)
+		if err != nil {
+			return nil, err
+		}
+		version, err := p.Store.Save(p.ID, next.storeState(), cur.Version)
+		if errors.Is(err, ErrVersionConflict) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		next.Version = version
+		return next, nil
+	}
+	return nil, ErrVersionConflict
+}
+
+func (p *Provider) storeState() StoreState {
+	return StoreState{Version: p.Version, Bookings: p.bookedSlots(), Services: p.ServiceBookings, Records: p.Records}
+}
+
+func (s StoreState) clone() StoreState {
+	s.Bookings = append([]slot.TimeSlot(nil), s.Bookings...)
+	s.Services = append([]ServiceBooking(nil), s.Services...)
+	var records []Booking
+	for _, b := range s.Records {
+		b.Customer = copyMetadata(b.Customer)
+		records = append(records, b)
+	}
+	s.Records = records
+	return s
+}
+
+// saveState returns next as the state following cur, or ErrVersionConflict
+// when cur is no longer at expected.
+func saveState(cur, next StoreState, expected int64) (StoreState, error) {
+	if cur.Version != expected {
+		return cur, ErrVersionConflict
+	}
+	next = next.clone()
+	next.Version = expected + 1
+	return next, nil
+}
+
+// MemoryStore is a BookingStore held in memory. It is safe for concurrent
+// use.
+type MemoryStore struct {
+	mu   sync.Mutex
+	data map[string]StoreState
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: map[string]StoreState{}}
+}
+
+func (m *MemoryStore) Load(providerID string) (StoreState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.data[providerID].clone(), nil
+}
+
+func (m *MemoryStore) Save(providerID string, state StoreState, expected int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	next, err := saveState(m.data[providerID], state, expected)
+	if err != nil {
+		return 0, err
+	}
+	m.data[providerID] = next
+	return next.Version, nil
+}
+
+// FileStore is a BookingStore kept in a single JSON file. Every write
+// rewrites the file through a temporary file and a rename, so readers never
+// see a partial document. It is safe for concurrent use within a process;
+// share one FileStore per file rather than opening several.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileStore opens the store at path, creating an empty one if needed.
+func NewFileStore(path string) (*FileStore, error) {
+	f := &FileStore{path: path}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if err := f.write(map[string]StoreState{}); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	if _, err := f.read(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *FileStore) Load(providerID string) (StoreState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, err := f.read()
+	if err != nil {
+		return StoreState{}, err
+	}
+	return data[providerID], nil
+}
+
+func (f *FileStore) Save(providerID string, state StoreState, expected int64) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, err := f.read()
+	if err != nil {
+		return 0, err
+	}
+	next, err := saveState(data[providerID], state, expected)
+	if err != nil {
+		return 0, err
+	}
+	data[providerID] = next
+	if err := f.write(data); err != nil {
+		return 0, err
+	}
+	return next.Version, nil
+}
+
+func (f *FileStore) read() (map[string]StoreState, error) {
+	raw, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]StoreState{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("provider: invalid booking store %s: %w", f.path, err)
+	}
+	return data, nil
+}
+
+func (f *FileStore) write(data map[string]StoreState) error {
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
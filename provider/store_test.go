@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Melpic13/timeslot/availability"
+	"github.com/Melpic13/timeslot/query"
+	"github.com/Melpic13/timeslot/slot"
+)
+
+func TestMemoryStoreVersions(t *testing.T) {
+	store := NewMemoryStore()
+	s := slot.TimeSlot{Start: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), Location: time.UTC}
+	state := StoreState{Bookings: []slot.TimeSlot{s}, Records: []Booking{{ID: "b1", Slot: s, Customer: map[string]any{"name": "ada"}}}}
+	v, err := store.Save("p", state, 0)
+	if err != nil || v != 1 {
+		t.Fatalf("expected version 1, got %d (%v)", v, err)
+	}
+	if _, err := store.Save("p", StoreState{}, 0); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+	state.Records[0].Customer["name"] = "bob"
+	loaded, _ := store.Load("p")
+	if loaded.Version != 1 || len(loaded.Bookings) != 1 || loaded.Records[0].Customer["name"] != "ada" {
+		t.Fatalf("the store should keep its own copy, got %+v", loaded)
+	}
+	if v, err := store.Save("p", StoreState{}, 1); err != nil || v != 2 {
+		t.Fatalf("expected version 2, got %d (%v)", v, err)
+	}
+	if loaded, _ := store.Load("p"); len(loaded.Bookings) != 0 || loaded.Version != 2 {
+		t.Fatalf("unexpected state %+v", loaded)
+	}
+}
+
+func TestStoreBackedBookingIsAtomic(t *testing.T) {
+	for name, seats := range map[string]int{"single": 0, "capacity": 3} {
+		t.Run(name, func(t *testing.T) {
+			store := NewMemoryStore()
+			p, monday := capacityFixture(WithCapacity(seats), WithStore(store))
+			class := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			succeeded := 0
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					// Every goroutine starts from the same stale provider value.
+					if _, err := p.Book(class); err == nil {
+						mu.Lock()
+						succeeded++
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+			want := seats
+			if want < 1 {
+				want = 1
+			}
+			state, _ := store.Load(p.ID)
+			if succeeded != want || len(state.Bookings) != want || state.Version != int64(want) {
+				t.Fatalf("expected %d bookings, got %d successes, %d stored, version %d", want, succeeded, len(state.Bookings), state.Version)
+			}
+		})
+	}
+}
+
+func TestStoreBackedProviderReadsAndCancels(t *testing.T) {
+	store := NewMemoryStore()
+	p, monday := capacityFixture(WithStore(store))
+	first := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+
+	booked, err := p.Book(first)
+	if err != nil || booked.Version != 1 || booked.Availability.Bookings.Len() != 1 {
+		t.Fatalf("unexpected booking result %+v (%v)", booked, err)
+	}
+	// The stale provider value still sees the booking through the store.
+	if _, err := p.Book(first); err == nil {
+		t.Fatalf("expected double booking to be rejected")
+	}
+	if p.IsAvailable(first) || len(p.GetBookings(monday, monday.Add(24*time.Hour))) != 1 || p.Explain(first).Available {
+		t.Fatalf("stale provider reads should see the stored booking")
+	}
+	q := query.NewQuery().Duration(time.Hour).Between(monday, monday.Add(24*time.Hour)).Build()
+	slots, err := p.FindSlots(q)
+	if err != nil || len(slots) != 2 {
+		t.Fatalf("FindSlots should read bookings from the store, got %d (%v)", len(slots), err)
+	}
+	cancelled, err := p.CancelBooking(first)
+	if err != nil || cancelled.Version != 2 || cancelled.Availability.Bookings.Len() != 0 {
+		t.Fatalf("unexpected cancel result (%v)", err)
+	}
+	if _, err := p.CancelBooking(first); !errors.Is(err, ErrBookingNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestFileStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bookings.json")
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	p, monday := capacityFixture(WithStore(store))
+	first := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+	if _, err := p.Book(first); err != nil {
+		t.Fatalf("book: %v", err)
+	}
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	state, err := reopened.Load(p.ID)
+	if err != nil || state.Version != 1 || len(state.Bookings) != 1 || !state.Bookings[0].Start.Equal(first.Start) {
+		t.Fatalf("unexpected persisted state %+v (%v)", state, err)
+	}
+	if _, err := reopened.Save(p.ID, state, 0); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := NewFileStore(path); err == nil {
+		t.Fatalf("expected error for corrupt store")
+	}
+}
+
+// brokenStore fails every load.
+type brokenStore struct{ *MemoryStore }
+
+func (brokenStore) Load(string) (StoreState, error) {
+	return StoreState{}, errStoreDown
+}
+
+func TestStoreReadErrors(t *testing.T) {
+	p, monday := capacityFixture(WithCapacity(2), WithStore(brokenStore{NewMemoryStore()}))
+	s := slot.TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour), Location: time.UTC}
+	if p.IsAvailable(s) || p.RemainingSeats(s) != 0 {
+		t.Fatalf("an unreadable store should leave nothing available")
+	}
+	if _, err := p.LoadBookings(monday, monday.Add(24*time.Hour)); !errors.Is(err, errStoreDown) {
+		t.Fatalf("expected the store error, got %v", err)
+	}
+	if e := p.Explain(s); e.Available || len(e.Reasons) != 1 || e.Reasons[0].Code != availability.ReasonStore {
+		t.Fatalf("expected a store reason, got %+v", e)
+	}
+}
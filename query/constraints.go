@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Melpic13/timeslot/internal/errs"
 	"github.com/Melpic13/timeslot/slot"
 )
 
@@ -20,13 +21,13 @@ func NewTimeOfDay(hour, minute, second int) TimeOfDay {
 
 func (t TimeOfDay) Validate() error {
 	if t.Hour < 0 || t.Hour > 23 {
-		return fmt.Errorf("query: invalid hour")
+		return fmt.Errorf("%w: hour %d", errs.ErrInvalidTimeOfDay, t.Hour)
 	}
 	if t.Minute < 0 || t.Minute > 59 {
-		return fmt.Errorf("query: invalid minute")
+		return fmt.Errorf("%w: minute %d", errs.ErrInvalidTimeOfDay, t.Minute)
 	}
 	if t.Second < 0 || t.Second > 59 {
-		return fmt.Errorf("query: invalid second")
+		return fmt.Errorf("%w: second %d", errs.ErrInvalidTimeOfDay, t.Second)
 	}
 	return nil
 }
@@ -3,22 +3,32 @@ package query
 import (
 	"fmt"
 	"time"
+
+	"github.com/Melpic13/timeslot/clock"
+	"github.com/Melpic13/timeslot/internal/errs"
+	"github.com/Melpic13/timeslot/slot"
 )
 
 // Query defines search criteria for finding available slots.
 type Query struct {
 	Duration    time.Duration
+	Step        time.Duration
+	AlignStarts bool
 	From        time.Time
 	To          time.Time
 	Constraints []Constraint
 	Preferences []Preference
 	Limit       int
 	Location    *time.Location
+	// Service names a provider service whose duration and booking rules
+	// apply; Duration may then be left zero.
+	Service string
 }
 
 // QueryBuilder provides fluent API.
 type QueryBuilder struct {
 	query Query
+	clock clock.Clock
 }
 
 func NewQuery() *QueryBuilder {
@@ -30,6 +40,26 @@ func (b *QueryBuilder) Duration(d time.Duration) *QueryBuilder {
 	return b
 }
 
+// Step sets the increment between candidate starts, independent of the
+// slot duration. Zero steps by the duration.
+func (b *QueryBuilder) Step(d time.Duration) *QueryBuilder {
+	b.query.Step = d
+	return b
+}
+
+// AlignStarts anchors candidate starts to the step grid in the query
+// location rather than to the start of each free window.
+func (b *QueryBuilder) AlignStarts() *QueryBuilder {
+	b.query.AlignStarts = true
+	return b
+}
+
+// Service searches for slots of the named provider service.
+func (b *QueryBuilder) Service(id string) *QueryBuilder {
+	b.query.Service = id
+	return b
+}
+
 func (b *QueryBuilder) Between(from, to time.Time) *QueryBuilder {
 	b.query.From = from
 	b.query.To = to
@@ -46,8 +76,15 @@ func (b *QueryBuilder) To(t time.Time) *QueryBuilder {
 	return b
 }
 
+// WithClock sets the clock InNext and Build use for "now". Nil uses the
+// system clock.
+func (b *QueryBuilder) WithClock(c clock.Clock) *QueryBuilder {
+	b.clock = c
+	return b
+}
+
 func (b *QueryBuilder) InNext(d time.Duration) *QueryBuilder {
-	now := time.Now().In(b.locationOrUTC())
+	now := clock.Or(b.clock).Now().In(b.locationOrUTC())
 	b.query.From = now
 	b.query.To = now.Add(d)
 	return b
@@ -129,9 +166,9 @@ func (b *QueryBuilder) Build() Query {
 		q.Location = time.UTC
 	}
 	if q.From.IsZero() {
-		q.From = time.Now().In(q.Location)
+		q.From = clock.Or(b.clock).Now().In(q.Location)
 	}
-	if q.To.IsZero() && q.Duration > 0 {
+	if q.To.IsZero() && (q.Duration > 0 || q.Service != "") {
 		q.To = q.From.Add(7 * 24 * time.Hour)
 	}
 	return q
@@ -142,15 +179,27 @@ func (b *QueryBuilder) Validate() error {
 }
 
 func (q Query) Validate() error {
-	if q.Duration <= 0 {
-		return fmt.Errorf("query: duration must be positive")
+	if q.Duration < 0 || (q.Duration == 0 && q.Service == "") {
+		return fmt.Errorf("%w: query duration %v", errs.ErrInvalidDuration, q.Duration)
+	}
+	if q.Step < 0 {
+		return fmt.Errorf("%w: negative query step %v", errs.ErrInvalidDuration, q.Step)
 	}
 	if !q.To.After(q.From) {
-		return fmt.Errorf("query: to must be after from")
+		return errs.Wrap("query", slot.TimeSlot{Start: q.From, End: q.To, Location: q.Location}, errs.ErrInvalidTimeRange)
 	}
 	return nil
 }
 
+// Generator returns the candidate generator described by the query.
+func (q Query) Generator() slot.Generator {
+	opts := []slot.GeneratorOption{slot.WithStep(q.Step)}
+	if q.AlignStarts {
+		opts = append(opts, slot.WithAlignment(q.Location))
+	}
+	return slot.NewGenerator(q.Duration, opts...)
+}
+
 func (b *QueryBuilder) locationOrUTC() *time.Location {
 	if b.query.Location == nil {
 		return time.UTC
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Melpic13/timeslot/clock"
 	"github.com/Melpic13/timeslot/slot"
 )
 
@@ -26,3 +27,50 @@ func TestOptimizeSlots(t *testing.T) {
 		t.Fatalf("expected earlier slot first")
 	}
 }
+
+func TestQueryStepAndGenerator(t *testing.T) {
+	from := time.Date(2025, 1, 6, 9, 7, 0, 0, time.UTC)
+	q := NewQuery().Duration(time.Hour).Step(15*time.Minute).AlignStarts().Between(from, from.Add(2*time.Hour)).Build()
+	if err := q.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	free := slot.NewCollection(slot.TimeSlot{Start: q.From, End: q.To, Location: time.UTC})
+	got := q.Generator().Generate(free)
+	if len(got) == 0 || got[0].Start.Minute() != 15 {
+		t.Fatalf("expected aligned first candidate, got %v", got)
+	}
+	q.Step = -time.Minute
+	if err := q.Validate(); err == nil {
+		t.Fatalf("expected negative step error")
+	}
+}
+
+func TestQueryBuilderWithClock(t *testing.T) {
+	now := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	clk := clock.Fixed(now)
+	q := NewQuery().WithClock(clk).Duration(time.Hour).InNext(48 * time.Hour).Build()
+	if !q.From.Equal(now) || !q.To.Equal(now.Add(48*time.Hour)) {
+		t.Fatalf("InNext should use the injected clock, got %v-%v", q.From, q.To)
+	}
+	built := NewQuery().WithClock(clk).Duration(time.Hour).Build()
+	if !built.From.Equal(now) || !built.To.Equal(now.Add(7*24*time.Hour)) {
+		t.Fatalf("Build should default From to the injected clock, got %v-%v", built.From, built.To)
+	}
+	if got := NewQuery().WithClock(nil).Duration(time.Hour).Build(); got.From.IsZero() {
+		t.Fatalf("nil clock should fall back to the system clock")
+	}
+}
+
+func TestServiceQueryDefersDuration(t *testing.T) {
+	now := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	q := NewQuery().Service("checkup").WithClock(clock.Fixed(now)).Build()
+	if err := q.Validate(); err != nil {
+		t.Fatalf("expected a service query without duration to be valid: %v", err)
+	}
+	if q.Service != "checkup" || !q.To.Equal(now.Add(7*24*time.Hour)) {
+		t.Fatalf("unexpected query %+v", q)
+	}
+	if err := NewQuery().Between(now, now.Add(time.Hour)).Build().Validate(); err == nil {
+		t.Fatalf("expected a query without duration or service to fail")
+	}
+}
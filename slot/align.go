@@ -0,0 +1,95 @@
+package slot
+
+import (
+	"time"
+
+	"github.com/Melpic13/timeslot/internal/timeutil"
+)
+
+// Floor rounds t down to the nearest multiple of step counted from local
+// midnight in loc. A non-positive step returns t unchanged.
+func Floor(t time.Time, step time.Duration, loc *time.Location) time.Time {
+	if step <= 0 {
+		return t
+	}
+	if loc == nil {
+		loc = t.Location()
+	}
+	midnight := timeutil.StartOfDay(t, loc)
+	elapsed := t.Sub(midnight)
+	return midnight.Add(elapsed - elapsed%step).In(t.Location())
+}
+
+// Ceil rounds t up to the nearest multiple of step counted from local
+// midnight in loc. Times already on the grid are returned unchanged.
+func Ceil(t time.Time, step time.Duration, loc *time.Location) time.Time {
+	floor := Floor(t, step, loc)
+	if floor.Equal(t) {
+		return floor
+	}
+	next := floor.Add(step)
+	if loc == nil {
+		loc = t.Location()
+	}
+	// Grids restart at every local midnight, so a step that does not divide
+	// the day evenly must not carry over into the next day.
+	if day := timeutil.StartOfDay(next, loc); day.After(floor) {
+		return day.In(t.Location())
+	}
+	return next
+}
+
+// IsAligned reports whether t sits exactly on the grid.
+func IsAligned(t time.Time, step time.Duration, loc *time.Location) bool {
+	return Floor(t, step, loc).Equal(t)
+}
+
+// Align trims the slot inward so that Start is rounded up and End rounded
+// down to the grid. It returns false when nothing is left after trimming.
+func (s TimeSlot) Align(step time.Duration, loc *time.Location) (TimeSlot, bool) {
+	if s.IsZero() || !s.End.After(s.Start) {
+		return TimeSlot{}, false
+	}
+	if loc == nil {
+		loc = s.locationOrUTC()
+	}
+	start := Ceil(s.Start, step, loc)
+	end := Floor(s.End, step, loc)
+	if !end.After(start) {
+		return TimeSlot{}, false
+	}
+	out := s.locationOrUTC()
+	return TimeSlot{Start: start.In(out), End: end.In(out), Location: out, Metadata: cloneMetadata(s.Metadata)}, true
+}
+
+// SplitAligned splits the slot into chunks of duration whose boundaries
+// fall on the duration grid in loc, e.g. 09:07-11:00 split by 30 minutes
+// yields 09:30, 10:00 and 10:30. Partial chunks at either end are dropped.
+func (s TimeSlot) SplitAligned(duration time.Duration, loc *time.Location) []TimeSlot {
+	if duration <= 0 {
+		return nil
+	}
+	aligned, ok := s.Align(duration, loc)
+	if !ok {
+		return nil
+	}
+	var out []TimeSlot
+	for _, part := range aligned.Split(duration) {
+		if part.Duration() == duration {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// Align trims every slot in the collection to the grid, dropping slots
+// that are too short to contain a full grid interval.
+func (c SlotCollection) Align(step time.Duration, loc *time.Location) SlotCollection {
+	out := make([]TimeSlot, 0, len(c.slots))
+	for _, s := range c.slots {
+		if aligned, ok := s.Align(step, loc); ok {
+			out = append(out, aligned)
+		}
+	}
+	return SlotCollection{slots: out, location: c.location}
+}
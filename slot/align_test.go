@@ -0,0 +1,109 @@
+package slot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFloorCeilAndIsAligned(t *testing.T) {
+	loc := time.UTC
+	tm := time.Date(2025, 1, 6, 9, 7, 0, 0, loc)
+	if got := Floor(tm, 15*time.Minute, loc); !got.Equal(time.Date(2025, 1, 6, 9, 0, 0, 0, loc)) {
+		t.Fatalf("floor got %v", got)
+	}
+	if got := Ceil(tm, 15*time.Minute, loc); !got.Equal(time.Date(2025, 1, 6, 9, 15, 0, 0, loc)) {
+		t.Fatalf("ceil got %v", got)
+	}
+	onGrid := time.Date(2025, 1, 6, 9, 30, 0, 0, loc)
+	if got := Ceil(onGrid, 30*time.Minute, loc); !got.Equal(onGrid) {
+		t.Fatalf("ceil on grid should be identity, got %v", got)
+	}
+	if !IsAligned(onGrid, 30*time.Minute, loc) || IsAligned(tm, 30*time.Minute, loc) {
+		t.Fatalf("is aligned mismatch")
+	}
+	if got := Floor(tm, 0, loc); !got.Equal(tm) {
+		t.Fatalf("non-positive step should be identity")
+	}
+	if got := Floor(tm, time.Hour, nil); !got.Equal(time.Date(2025, 1, 6, 9, 0, 0, 0, loc)) {
+		t.Fatalf("nil location should use the time's location, got %v", got)
+	}
+
+	// 7h grid: 00:00, 07:00, 14:00, 21:00, then the next day restarts at 00:00.
+	late := time.Date(2025, 1, 6, 22, 0, 0, 0, loc)
+	if got := Ceil(late, 7*time.Hour, loc); !got.Equal(time.Date(2025, 1, 7, 0, 0, 0, 0, loc)) {
+		t.Fatalf("ceil should restart at midnight, got %v", got)
+	}
+}
+
+func TestAlignHalfHourTimezone(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skip("timezone DB unavailable")
+	}
+	// 09:07 Kolkata is 03:37 UTC; an hour grid in Kolkata must land on 10:00 local.
+	s := TimeSlot{Start: time.Date(2025, 1, 6, 3, 37, 0, 0, time.UTC), End: time.Date(2025, 1, 6, 7, 50, 0, 0, time.UTC), Location: time.UTC}
+	aligned, ok := s.Align(time.Hour, kolkata)
+	if !ok {
+		t.Fatalf("expected aligned slot")
+	}
+	if got := aligned.Start.In(kolkata); got.Hour() != 10 || got.Minute() != 0 {
+		t.Fatalf("start not on local hour: %v", got)
+	}
+	if got := aligned.End.In(kolkata); got.Hour() != 13 || got.Minute() != 0 {
+		t.Fatalf("end not on local hour: %v", got)
+	}
+	if aligned.Location != time.UTC {
+		t.Fatalf("aligned slot should keep its own location")
+	}
+}
+
+func TestSlotAlignAndSplitAligned(t *testing.T) {
+	loc := time.UTC
+	s := TimeSlot{Start: time.Date(2025, 1, 6, 9, 7, 0, 0, loc), End: time.Date(2025, 1, 6, 11, 0, 0, 0, loc), Location: loc, Metadata: map[string]any{"k": "v"}}
+	parts := s.SplitAligned(30*time.Minute, loc)
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 aligned parts, got %d", len(parts))
+	}
+	for i, want := range []int{30, 0, 30} {
+		if parts[i].Start.Minute() != want || parts[i].Duration() != 30*time.Minute {
+			t.Fatalf("part %d unexpected: %s", i, parts[i])
+		}
+	}
+	if parts[0].Metadata["k"] != "v" {
+		t.Fatalf("expected metadata copied")
+	}
+
+	short := TimeSlot{Start: time.Date(2025, 1, 6, 9, 7, 0, 0, loc), End: time.Date(2025, 1, 6, 9, 20, 0, 0, loc), Location: loc}
+	if _, ok := short.Align(15*time.Minute, loc); ok {
+		t.Fatalf("expected slot shorter than grid to vanish")
+	}
+	if got := short.SplitAligned(15*time.Minute, loc); got != nil {
+		t.Fatalf("expected no aligned parts")
+	}
+	if got := s.SplitAligned(0, loc); got != nil {
+		t.Fatalf("expected nil for zero duration")
+	}
+	if _, ok := (TimeSlot{}).Align(time.Minute, nil); ok {
+		t.Fatalf("zero slot should not align")
+	}
+}
+
+func TestCollectionAlign(t *testing.T) {
+	loc := time.UTC
+	c := NewCollection(
+		TimeSlot{Start: time.Date(2025, 1, 6, 9, 7, 0, 0, loc), End: time.Date(2025, 1, 6, 10, 52, 0, 0, loc), Location: loc},
+		TimeSlot{Start: time.Date(2025, 1, 6, 12, 5, 0, 0, loc), End: time.Date(2025, 1, 6, 12, 25, 0, 0, loc), Location: loc},
+		TimeSlot{Start: time.Date(2025, 1, 6, 14, 0, 0, 0, loc), End: time.Date(2025, 1, 6, 15, 0, 0, 0, loc), Location: loc},
+	)
+	aligned := c.Align(15*time.Minute, loc)
+	if aligned.Len() != 2 {
+		t.Fatalf("expected short slot dropped, got %d", aligned.Len())
+	}
+	first, _ := aligned.First()
+	if first.Start.Minute() != 15 || first.End.Minute() != 45 {
+		t.Fatalf("unexpected first aligned slot: %s", first)
+	}
+	if got := c.Align(time.Hour, loc); got.Len() != 1 {
+		t.Fatalf("expected only the whole-hour slot to survive, got %d", got.Len())
+	}
+}
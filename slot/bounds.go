@@ -0,0 +1,166 @@
+package slot
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidBounds = errors.New("slot: invalid boundary mode")
+
+// Bounds selects which endpoints of a slot are part of it. The zero value
+// is half-open, [Start, End), which is what every slot used before bounds
+// were introduced.
+type Bounds int
+
+const (
+	BoundsHalfOpen Bounds = iota // [Start, End)
+	BoundsClosed                 // [Start, End]
+	BoundsOpen                   // (Start, End)
+	BoundsLeftOpen               // (Start, End]
+)
+
+func (b Bounds) IncludesStart() bool {
+	return b == BoundsHalfOpen || b == BoundsClosed
+}
+
+func (b Bounds) IncludesEnd() bool {
+	return b == BoundsClosed || b == BoundsLeftOpen
+}
+
+func (b Bounds) Validate() error {
+	if b < BoundsHalfOpen || b > BoundsLeftOpen {
+		return ErrInvalidBounds
+	}
+	return nil
+}
+
+func (b Bounds) String() string {
+	switch b {
+	case BoundsHalfOpen:
+		return "[)"
+	case BoundsClosed:
+		return "[]"
+	case BoundsOpen:
+		return "()"
+	case BoundsLeftOpen:
+		return "(]"
+	default:
+		return fmt.Sprintf("Bounds(%d)", int(b))
+	}
+}
+
+func (b Bounds) MarshalText() ([]byte, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	return []byte(b.String()), nil
+}
+
+func (b *Bounds) UnmarshalText(text []byte) error {
+	for _, candidate := range []Bounds{BoundsHalfOpen, BoundsClosed, BoundsOpen, BoundsLeftOpen} {
+		if candidate.String() == string(text) {
+			*b = candidate
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidBounds, string(text))
+}
+
+func boundsOf(includeStart, includeEnd bool) Bounds {
+	switch {
+	case includeStart && includeEnd:
+		return BoundsClosed
+	case includeStart:
+		return BoundsHalfOpen
+	case includeEnd:
+		return BoundsLeftOpen
+	default:
+		return BoundsOpen
+	}
+}
+
+// emptyRange reports whether the range between start and end, with the
+// given endpoint inclusion, contains no instant at all.
+func emptyRange(start, end time.Time, includeStart, includeEnd bool) bool {
+	if end.Before(start) {
+		return true
+	}
+	return end.Equal(start) && !(includeStart && includeEnd)
+}
+
+func laterStart(a, b TimeSlot) (time.Time, bool) {
+	switch {
+	case a.Start.After(b.Start):
+		return a.Start, a.Bounds.IncludesStart()
+	case b.Start.After(a.Start):
+		return b.Start, b.Bounds.IncludesStart()
+	default:
+		return a.Start, a.Bounds.IncludesStart() && b.Bounds.IncludesStart()
+	}
+}
+
+func earlierEnd(a, b TimeSlot) (time.Time, bool) {
+	switch {
+	case a.End.Before(b.End):
+		return a.End, a.Bounds.IncludesEnd()
+	case b.End.Before(a.End):
+		return b.End, b.Bounds.IncludesEnd()
+	default:
+		return a.End, a.Bounds.IncludesEnd() && b.Bounds.IncludesEnd()
+	}
+}
+
+func earlierStart(a, b TimeSlot) (time.Time, bool) {
+	switch {
+	case a.Start.Before(b.Start):
+		return a.Start, a.Bounds.IncludesStart()
+	case b.Start.Before(a.Start):
+		return b.Start, b.Bounds.IncludesStart()
+	default:
+		return a.Start, a.Bounds.IncludesStart() || b.Bounds.IncludesStart()
+	}
+}
+
+func laterEnd(a, b TimeSlot) (time.Time, bool) {
+	switch {
+	case a.End.After(b.End):
+		return a.End, a.Bounds.IncludesEnd()
+	case b.End.After(a.End):
+		return b.End, b.Bounds.IncludesEnd()
+	default:
+		return a.End, a.Bounds.IncludesEnd() || b.Bounds.IncludesEnd()
+	}
+}
+
+// touches reports whether a ends exactly where b starts with the shared
+// instant belonging to at least one of them, so the two can be joined.
+func touches(a, b TimeSlot) bool {
+	return a.End.Equal(b.Start) && (a.Bounds.IncludesEnd() || b.Bounds.IncludesStart())
+}
+
+// Instant is a single point in time. It converts to a closed, zero-length
+// slot so it can be stored in and queried against a SlotCollection.
+type Instant struct {
+	Time     time.Time
+	Metadata map[string]any
+}
+
+func NewInstant(t time.Time) Instant {
+	return Instant{Time: t}
+}
+
+func (i Instant) Slot() TimeSlot {
+	loc := i.Time.Location()
+	return TimeSlot{Start: i.Time, End: i.Time, Location: loc, Bounds: BoundsClosed, Metadata: cloneMetadata(i.Metadata)}
+}
+
+// Within reports whether the instant lies inside s, honoring its bounds.
+func (i Instant) Within(s TimeSlot) bool {
+	return s.Contains(i.Time)
+}
+
+// IsInstant reports whether the slot is a closed, zero-length point.
+func (s TimeSlot) IsInstant() bool {
+	return !s.IsZero() && s.Start.Equal(s.End) && s.Bounds == BoundsClosed
+}
@@ -0,0 +1,165 @@
+package slot
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBoundsContains(t *testing.T) {
+	loc := time.UTC
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, loc)
+	end := time.Date(2025, 1, 6, 10, 0, 0, 0, loc)
+	mid := start.Add(30 * time.Minute)
+
+	tests := []struct {
+		bounds             Bounds
+		start, mid, end    bool
+		inclStart, inclEnd bool
+	}{
+		{BoundsHalfOpen, true, true, false, true, false},
+		{BoundsClosed, true, true, true, true, true},
+		{BoundsOpen, false, true, false, false, false},
+		{BoundsLeftOpen, false, true, true, false, true},
+	}
+	for _, tt := range tests {
+		s := TimeSlot{Start: start, End: end, Location: loc, Bounds: tt.bounds}
+		if s.Contains(start) != tt.start || s.Contains(mid) != tt.mid || s.Contains(end) != tt.end {
+			t.Fatalf("%s: contains mismatch", tt.bounds)
+		}
+		if tt.bounds.IncludesStart() != tt.inclStart || tt.bounds.IncludesEnd() != tt.inclEnd {
+			t.Fatalf("%s: inclusion mismatch", tt.bounds)
+		}
+	}
+}
+
+func TestBoundsOverlapsAndValidate(t *testing.T) {
+	loc := time.UTC
+	nine := time.Date(2025, 1, 6, 9, 0, 0, 0, loc)
+	ten := nine.Add(time.Hour)
+	eleven := ten.Add(time.Hour)
+
+	closed := TimeSlot{Start: nine, End: ten, Location: loc, Bounds: BoundsClosed}
+	next := TimeSlot{Start: ten, End: eleven, Location: loc}
+	if !closed.Overlaps(next) {
+		t.Fatalf("closed end should overlap half-open start at the same instant")
+	}
+	halfOpen := TimeSlot{Start: nine, End: ten, Location: loc}
+	if halfOpen.Overlaps(next) {
+		t.Fatalf("adjacent half-open slots must not overlap")
+	}
+	openNext := TimeSlot{Start: ten, End: eleven, Location: loc, Bounds: BoundsOpen}
+	if closed.Overlaps(openNext) {
+		t.Fatalf("closed end should not overlap open start")
+	}
+
+	inter, ok := closed.Intersection(next)
+	if !ok || !inter.IsInstant() {
+		t.Fatalf("expected instant intersection, got %v %v", inter, ok)
+	}
+	u, err := halfOpen.Union(TimeSlot{Start: ten, End: ten, Location: loc, Bounds: BoundsClosed})
+	if err != nil || u.Bounds != BoundsClosed {
+		t.Fatalf("union with trailing instant should be closed: %v %v", u, err)
+	}
+	if _, err := halfOpen.Union(openNext); !errors.Is(err, ErrUnionDisjoint) {
+		t.Fatalf("10:00 belongs to neither slot, expected a disjoint union, got %v", err)
+	}
+	if _, err := openNext.Union(halfOpen); !errors.Is(err, ErrUnionDisjoint) {
+		t.Fatalf("union should not depend on order, got %v", err)
+	}
+
+	if _, err := NewWithBounds(ten, ten, BoundsClosed); err != nil {
+		t.Fatalf("closed zero-length slot should be valid: %v", err)
+	}
+	for _, b := range []Bounds{BoundsHalfOpen, BoundsOpen, BoundsLeftOpen} {
+		if _, err := NewWithBounds(ten, ten, b); !errors.Is(err, ErrInvalidTimeRange) {
+			t.Fatalf("%s zero-length slot should be invalid", b)
+		}
+	}
+	if _, err := NewWithBounds(nine, ten, Bounds(42)); !errors.Is(err, ErrInvalidBounds) {
+		t.Fatalf("expected invalid bounds error")
+	}
+	if Bounds(42).String() == "" {
+		t.Fatalf("unknown bounds should still format")
+	}
+	if closed.Equal(halfOpen) {
+		t.Fatalf("bounds should participate in equality")
+	}
+}
+
+func TestBoundsJSON(t *testing.T) {
+	loc := time.UTC
+	s := TimeSlot{Start: time.Date(2025, 1, 6, 9, 0, 0, 0, loc), End: time.Date(2025, 1, 6, 10, 0, 0, 0, loc), Location: loc, Bounds: BoundsLeftOpen}
+	b, err := json.Marshal(s)
+	if err != nil || !strings.Contains(string(b), `"bounds":"(]"`) {
+		t.Fatalf("marshal bounds: %v %s", err, b)
+	}
+	var got TimeSlot
+	if err := json.Unmarshal(b, &got); err != nil || got.Bounds != BoundsLeftOpen {
+		t.Fatalf("unmarshal bounds: %v %v", err, got.Bounds)
+	}
+
+	plain, _ := json.Marshal(TimeSlot{Start: s.Start, End: s.End, Location: loc})
+	if strings.Contains(string(plain), "bounds") {
+		t.Fatalf("half-open bounds should be omitted: %s", plain)
+	}
+	bad := []byte(`{"start":"2025-01-01T09:00:00Z","end":"2025-01-01T10:00:00Z","bounds":"[["}`)
+	if err := json.Unmarshal(bad, &got); err == nil {
+		t.Fatalf("expected invalid bounds error")
+	}
+	if _, err := Bounds(9).MarshalText(); err == nil {
+		t.Fatalf("expected marshal error for invalid bounds")
+	}
+}
+
+func TestInstantInCollections(t *testing.T) {
+	loc := time.UTC
+	nine := time.Date(2025, 1, 6, 9, 0, 0, 0, loc)
+	c := NewCollection(
+		TimeSlot{Start: nine, End: nine.Add(time.Hour), Location: loc},
+		TimeSlot{Start: nine.Add(2 * time.Hour), End: nine.Add(3 * time.Hour), Location: loc},
+	)
+
+	deadline := NewInstant(nine.Add(2 * time.Hour))
+	if got := c.FindOverlaps(deadline.Slot()); len(got) != 1 {
+		t.Fatalf("instant at slot start should match one slot, got %d", len(got))
+	}
+	if got := c.FindOverlaps(NewInstant(nine.Add(time.Hour)).Slot()); len(got) != 0 {
+		t.Fatalf("instant at half-open end should not match")
+	}
+	if !deadline.Within(TimeSlot{Start: nine, End: deadline.Time, Location: loc, Bounds: BoundsClosed}) {
+		t.Fatalf("instant should sit inside inclusive range")
+	}
+
+	withInstant := c.Add(NewInstant(nine.Add(90 * time.Minute)).Slot())
+	if withInstant.Len() != 3 {
+		t.Fatalf("instant should be stored as its own element, got %d", withInstant.Len())
+	}
+	if withInstant.TotalDuration() != c.TotalDuration() {
+		t.Fatalf("instants must not add duration")
+	}
+
+	within := TimeSlot{Start: nine, End: nine.Add(3 * time.Hour), Location: loc}
+	gaps := withInstant.Gaps(within)
+	if gaps.Len() != 2 {
+		t.Fatalf("instant should split the gap, got %d", gaps.Len())
+	}
+	first, _ := gaps.First()
+	if first.Bounds != BoundsHalfOpen || !first.End.Equal(nine.Add(90*time.Minute)) {
+		t.Fatalf("unexpected first gap %v %s", first, first.Bounds)
+	}
+	last, _ := gaps.Last()
+	if last.Bounds != BoundsOpen {
+		t.Fatalf("gap after an instant should exclude it, got %s", last.Bounds)
+	}
+
+	punched := NewCollection(within).Subtract(NewCollection(deadline.Slot()))
+	if punched.Len() != 2 || punched.TotalDuration() != 3*time.Hour {
+		t.Fatalf("subtracting an instant should punch a zero-width hole, got %d", punched.Len())
+	}
+	if punched.Add(deadline.Slot()).Len() != 1 {
+		t.Fatalf("adding the instant back should merge the hole")
+	}
+}
@@ -41,7 +41,7 @@ func (c SlotCollection) Merge() SlotCollection {
 	cur := sorted[0]
 	for i := 1; i < len(sorted); i++ {
 		next := sorted[i]
-		if cur.Overlaps(next) || cur.End.Equal(next.Start) {
+		if cur.Overlaps(next) || touches(cur, next) {
 			u, _ := cur.Union(next)
 			cur = u
 			continue
@@ -62,11 +62,13 @@ func (c SlotCollection) Subtract(other SlotCollection) SlotCollection {
 				next = append(next, s)
 				continue
 			}
-			if cut.Start.After(s.Start) {
-				next = append(next, TimeSlot{Start: s.Start, End: cut.Start, Location: s.locationOrUTC()})
+			includeStart, includeEnd := s.Bounds.IncludesStart(), !cut.Bounds.IncludesStart()
+			if !emptyRange(s.Start, cut.Start, includeStart, includeEnd) {
+				next = append(next, TimeSlot{Start: s.Start, End: cut.Start, Location: s.locationOrUTC(), Bounds: boundsOf(includeStart, includeEnd)})
 			}
-			if cut.End.Before(s.End) {
-				next = append(next, TimeSlot{Start: cut.End, End: s.End, Location: s.locationOrUTC()})
+			includeStart, includeEnd = !cut.Bounds.IncludesEnd(), s.Bounds.IncludesEnd()
+			if !emptyRange(cut.End, s.End, includeStart, includeEnd) {
+				next = append(next, TimeSlot{Start: cut.End, End: s.End, Location: s.locationOrUTC(), Bounds: boundsOf(includeStart, includeEnd)})
 			}
 		}
 		remaining = next
@@ -115,7 +117,7 @@ func (c SlotCollection) FindOverlaps(slot TimeSlot) []TimeSlot {
 	})
 	out := make([]TimeSlot, 0)
 	for i := idx; i < len(c.slots); i++ {
-		if !c.slots[i].Start.Before(slot.End) {
+		if c.slots[i].Start.After(slot.End) {
 			break
 		}
 		if c.slots[i].Overlaps(slot) {
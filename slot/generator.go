@@ -0,0 +1,114 @@
+package slot
+
+import "time"
+
+// Generator produces fixed-length candidate slots inside free windows,
+// advancing by a step that may differ from the slot duration.
+type Generator struct {
+	duration time.Duration
+	step     time.Duration
+	stagger  time.Duration
+	align    *time.Location
+}
+
+// GeneratorOption configures a Generator.
+type GeneratorOption func(*Generator)
+
+// WithStep sets the increment between candidate starts. Non-positive
+// values fall back to the slot duration.
+func WithStep(d time.Duration) GeneratorOption {
+	return func(g *Generator) { g.step = d }
+}
+
+// WithStagger offsets every candidate start by d, e.g. :05, :20, :35 with a
+// 15-minute step.
+func WithStagger(d time.Duration) GeneratorOption {
+	return func(g *Generator) { g.stagger = d }
+}
+
+// WithAlignment anchors candidate starts to the step grid counted from
+// local midnight in loc instead of to the start of each free window.
+func WithAlignment(loc *time.Location) GeneratorOption {
+	return func(g *Generator) {
+		if loc == nil {
+			loc = time.UTC
+		}
+		g.align = loc
+	}
+}
+
+func NewGenerator(duration time.Duration, opts ...GeneratorOption) Generator {
+	g := Generator{duration: duration}
+	for _, opt := range opts {
+		opt(&g)
+	}
+	if g.step <= 0 {
+		g.step = duration
+	}
+	return g
+}
+
+func (g Generator) Duration() time.Duration {
+	return g.duration
+}
+
+func (g Generator) Step() time.Duration {
+	return g.step
+}
+
+// Iterate returns a lazy iterator over the candidates inside free.
+func (g Generator) Iterate(free SlotCollection) *SlotIterator {
+	return &SlotIterator{gen: g, windows: free.slots, idx: -1}
+}
+
+// Generate collects every candidate inside free.
+func (g Generator) Generate(free SlotCollection) []TimeSlot {
+	var out []TimeSlot
+	it := g.Iterate(free)
+	for s, ok := it.Next(); ok; s, ok = it.Next() {
+		out = append(out, s)
+	}
+	return out
+}
+
+func (g Generator) firstStart(window TimeSlot) time.Time {
+	start := window.Start.Add(g.stagger)
+	if g.align != nil {
+		start = Ceil(window.Start.Add(-g.stagger), g.step, g.align).Add(g.stagger).In(window.Start.Location())
+	}
+	for start.Before(window.Start) {
+		start = start.Add(g.step)
+	}
+	return start
+}
+
+// SlotIterator walks generated candidates one at a time.
+type SlotIterator struct {
+	gen     Generator
+	windows []TimeSlot
+	idx     int
+	cur     time.Time
+}
+
+// Next returns the next candidate, or false once the windows are exhausted.
+func (it *SlotIterator) Next() (TimeSlot, bool) {
+	if it == nil || it.gen.duration <= 0 {
+		return TimeSlot{}, false
+	}
+	for it.idx < len(it.windows) {
+		if it.idx >= 0 {
+			w := it.windows[it.idx]
+			end := it.cur.Add(it.gen.duration)
+			if !end.After(w.End) {
+				out := TimeSlot{Start: it.cur, End: end, Location: w.locationOrUTC()}
+				it.cur = it.cur.Add(it.gen.step)
+				return out, true
+			}
+		}
+		it.idx++
+		if it.idx < len(it.windows) {
+			it.cur = it.gen.firstStart(it.windows[it.idx])
+		}
+	}
+	return TimeSlot{}, false
+}
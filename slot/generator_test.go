@@ -0,0 +1,85 @@
+package slot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGeneratorDefaultStepMatchesDuration(t *testing.T) {
+	loc := time.UTC
+	free := NewCollection(TimeSlot{Start: time.Date(2025, 1, 6, 9, 0, 0, 0, loc), End: time.Date(2025, 1, 6, 11, 30, 0, 0, loc), Location: loc})
+	got := NewGenerator(time.Hour).Generate(free)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 candidates, got %d", len(got))
+	}
+	if NewGenerator(time.Hour).Step() != time.Hour || NewGenerator(time.Hour).Duration() != time.Hour {
+		t.Fatalf("unexpected generator accessors")
+	}
+}
+
+func TestGeneratorSlidingWindow(t *testing.T) {
+	loc := time.UTC
+	free := NewCollection(
+		TimeSlot{Start: time.Date(2025, 1, 6, 9, 0, 0, 0, loc), End: time.Date(2025, 1, 6, 11, 30, 0, 0, loc), Location: loc},
+		TimeSlot{Start: time.Date(2025, 1, 6, 13, 0, 0, 0, loc), End: time.Date(2025, 1, 6, 14, 0, 0, 0, loc), Location: loc},
+	)
+	got := NewGenerator(time.Hour, WithStep(30*time.Minute)).Generate(free)
+	want := []string{"09:00", "09:30", "10:00", "10:30", "13:00"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d candidates, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		if got[i].Start.Format("15:04") != w || got[i].Duration() != time.Hour {
+			t.Fatalf("candidate %d = %s, want start %s", i, got[i], w)
+		}
+	}
+}
+
+func TestGeneratorAlignmentAndStagger(t *testing.T) {
+	loc := time.UTC
+	free := NewCollection(TimeSlot{Start: time.Date(2025, 1, 6, 9, 7, 0, 0, loc), End: time.Date(2025, 1, 6, 10, 30, 0, 0, loc), Location: loc})
+
+	aligned := NewGenerator(30*time.Minute, WithStep(15*time.Minute), WithAlignment(loc)).Generate(free)
+	if len(aligned) == 0 || aligned[0].Start.Format("15:04") != "09:15" {
+		t.Fatalf("expected first aligned candidate at 09:15, got %v", aligned)
+	}
+
+	staggered := NewGenerator(30*time.Minute, WithStep(15*time.Minute), WithAlignment(nil), WithStagger(5*time.Minute)).Generate(free)
+	if len(staggered) == 0 || staggered[0].Start.Format("15:04") != "09:20" {
+		t.Fatalf("expected first staggered candidate at 09:20, got %v", staggered)
+	}
+	last := staggered[len(staggered)-1]
+	if last.End.After(free.slots[0].End) {
+		t.Fatalf("candidate exceeds window: %s", last)
+	}
+
+	unaligned := NewGenerator(30*time.Minute, WithStagger(-10*time.Minute)).Generate(free)
+	if len(unaligned) == 0 || unaligned[0].Start.Before(free.slots[0].Start) {
+		t.Fatalf("candidates must not start before the window")
+	}
+}
+
+func TestSlotIteratorLazy(t *testing.T) {
+	loc := time.UTC
+	free := NewCollection(TimeSlot{Start: time.Date(2025, 1, 6, 0, 0, 0, 0, loc), End: time.Date(2026, 1, 6, 0, 0, 0, 0, loc), Location: loc})
+	it := NewGenerator(time.Minute).Iterate(free)
+	first, ok := it.Next()
+	if !ok || !first.Start.Equal(free.slots[0].Start) {
+		t.Fatalf("unexpected first candidate")
+	}
+	second, ok := it.Next()
+	if !ok || !second.Start.Equal(first.End) {
+		t.Fatalf("unexpected second candidate")
+	}
+
+	if _, ok := NewGenerator(0).Iterate(free).Next(); ok {
+		t.Fatalf("zero duration should yield nothing")
+	}
+	if _, ok := NewGenerator(time.Hour).Iterate(NewCollection()).Next(); ok {
+		t.Fatalf("empty collection should yield nothing")
+	}
+	var nilIt *SlotIterator
+	if _, ok := nilIt.Next(); ok {
+		t.Fatalf("nil iterator should yield nothing")
+	}
+}
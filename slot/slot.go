@@ -18,6 +18,7 @@ type TimeSlot struct {
 	Start    time.Time      `json:"start"`
 	End      time.Time      `json:"end"`
 	Location *time.Location `json:"-"`
+	Bounds   Bounds         `json:"bounds,omitempty"`
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
@@ -26,39 +27,50 @@ func New(start, end time.Time) (TimeSlot, error) {
 	return ts, ts.Validate()
 }
 
+// NewWithBounds creates a slot with explicit boundary semantics. Closed
+// slots may be zero-length.
+func NewWithBounds(start, end time.Time, bounds Bounds) (TimeSlot, error) {
+	ts := TimeSlot{Start: start, End: end, Location: start.Location(), Bounds: bounds}
+	return ts, ts.Validate()
+}
+
 func (s TimeSlot) Duration() time.Duration {
 	return s.End.Sub(s.Start)
 }
 
 func (s TimeSlot) Contains(t time.Time) bool {
-	return (t.Equal(s.Start) || t.After(s.Start)) && t.Before(s.End)
+	afterStart := t.After(s.Start) || (s.Bounds.IncludesStart() && t.Equal(s.Start))
+	beforeEnd := t.Before(s.End) || (s.Bounds.IncludesEnd() && t.Equal(s.End))
+	return afterStart && beforeEnd
 }
 
 func (s TimeSlot) Overlaps(other TimeSlot) bool {
-	return s.Start.Before(other.End) && other.Start.Before(s.End)
+	start, includeStart := laterStart(s, other)
+	end, includeEnd := earlierEnd(s, other)
+	return !emptyRange(start, end, includeStart, includeEnd)
 }
 
 func (s TimeSlot) Intersection(other TimeSlot) (TimeSlot, bool) {
 	if !s.Overlaps(other) {
 		return TimeSlot{}, false
 	}
-	start := maxTime(s.Start, other.Start)
-	end := minTime(s.End, other.End)
+	start, includeStart := laterStart(s, other)
+	end, includeEnd := earlierEnd(s, other)
 	loc := s.locationOrUTC()
 	if other.Location != nil {
 		loc = other.Location
 	}
-	return TimeSlot{Start: start.In(loc), End: end.In(loc), Location: loc}, true
+	return TimeSlot{Start: start.In(loc), End: end.In(loc), Location: loc, Bounds: boundsOf(includeStart, includeEnd)}, true
 }
 
 func (s TimeSlot) Union(other TimeSlot) (TimeSlot, error) {
-	if !s.Overlaps(other) && !s.End.Equal(other.Start) && !other.End.Equal(s.Start) {
+	if !s.Overlaps(other) && !touches(s, other) && !touches(other, s) {
 		return TimeSlot{}, ErrUnionDisjoint
 	}
-	start := minTime(s.Start, other.Start)
-	end := maxTime(s.End, other.End)
+	start, includeStart := earlierStart(s, other)
+	end, includeEnd := laterEnd(s, other)
 	loc := s.locationOrUTC()
-	return TimeSlot{Start: start.In(loc), End: end.In(loc), Location: loc}, nil
+	return TimeSlot{Start: start.In(loc), End: end.In(loc), Location: loc, Bounds: boundsOf(includeStart, includeEnd)}, nil
 }
 
 func (s TimeSlot) Split(duration time.Duration) []TimeSlot {
@@ -81,14 +93,14 @@ func (s TimeSlot) Split(duration time.Duration) []TimeSlot {
 }
 
 func (s TimeSlot) Shift(d time.Duration) TimeSlot {
-	return TimeSlot{Start: s.Start.Add(d), End: s.End.Add(d), Location: s.locationOrUTC(), Metadata: cloneMetadata(s.Metadata)}
+	return TimeSlot{Start: s.Start.Add(d), End: s.End.Add(d), Location: s.locationOrUTC(), Bounds: s.Bounds, Metadata: cloneMetadata(s.Metadata)}
 }
 
 func (s TimeSlot) InTimezone(loc *time.Location) TimeSlot {
 	if loc == nil {
 		loc = time.UTC
 	}
-	return TimeSlot{Start: s.Start.In(loc), End: s.End.In(loc), Location: loc, Metadata: cloneMetadata(s.Metadata)}
+	return TimeSlot{Start: s.Start.In(loc), End: s.End.In(loc), Location: loc, Bounds: s.Bounds, Metadata: cloneMetadata(s.Metadata)}
 }
 
 func (s TimeSlot) IsZero() bool {
@@ -99,14 +111,17 @@ func (s TimeSlot) Validate() error {
 	if s.IsZero() {
 		return ErrInvalidTimeRange
 	}
-	if !s.End.After(s.Start) {
+	if err := s.Bounds.Validate(); err != nil {
+		return err
+	}
+	if emptyRange(s.Start, s.End, s.Bounds.IncludesStart(), s.Bounds.IncludesEnd()) {
 		return ErrInvalidTimeRange
 	}
 	return nil
 }
 
 func (s TimeSlot) Equal(other TimeSlot) bool {
-	if !s.Start.Equal(other.Start) || !s.End.Equal(other.End) {
+	if !s.Start.Equal(other.Start) || !s.End.Equal(other.End) || s.Bounds != other.Bounds {
 		return false
 	}
 	if s.locationOrUTC().String() != other.locationOrUTC().String() {
@@ -124,12 +139,14 @@ func (s TimeSlot) MarshalJSON() ([]byte, error) {
 		Start    time.Time      `json:"start"`
 		End      time.Time      `json:"end"`
 		Location string         `json:"location,omitempty"`
+		Bounds   Bounds         `json:"bounds,omitempty"`
 		Metadata map[string]any `json:"metadata,omitempty"`
 	}
 	return json.Marshal(alias{
 		Start:    s.Start,
 		End:      s.End,
 		Location: s.locationOrUTC().String(),
+		Bounds:   s.Bounds,
 		Metadata: s.Metadata,
 	})
 }
@@ -139,6 +156,7 @@ func (s *TimeSlot) UnmarshalJSON(data []byte) error {
 		Start    time.Time      `json:"start"`
 		End      time.Time      `json:"end"`
 		Location string         `json:"location"`
+		Bounds   Bounds         `json:"bounds"`
 		Metadata map[string]any `json:"metadata"`
 	}
 	if err := json.Unmarshal(data, &raw); err != nil {
@@ -155,6 +173,7 @@ func (s *TimeSlot) UnmarshalJSON(data []byte) error {
 	s.Start = raw.Start.In(loc)
 	s.End = raw.End.In(loc)
 	s.Location = loc
+	s.Bounds = raw.Bounds
 	s.Metadata = cloneMetadata(raw.Metadata)
 	return s.Validate()
 }
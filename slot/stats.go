@@ -0,0 +1,221 @@
+package slot
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Melpic13/timeslot/internal/timeutil"
+)
+
+// Period selects the calendar bucket used by statistics.
+type Period int
+
+const (
+	PeriodDay Period = iota
+	PeriodWeek
+	PeriodMonth
+)
+
+func (p Period) String() string {
+	switch p {
+	case PeriodDay:
+		return "day"
+	case PeriodWeek:
+		return "week"
+	case PeriodMonth:
+		return "month"
+	default:
+		return "unknown"
+	}
+}
+
+// Stats summarizes the durations in a set of slots.
+type Stats struct {
+	Count int
+	Total time.Duration
+	Mean  time.Duration
+	Min   time.Duration
+	Max   time.Duration
+	// Fragmentation is 0 when all time is in one contiguous slot and
+	// approaches 1 as the same time is spread across many small slots.
+	Fragmentation float64
+}
+
+// Bucket holds the statistics for one calendar period.
+type Bucket struct {
+	Period Period
+	Start  time.Time
+	End    time.Time
+	Slots  SlotCollection
+	Stats  Stats
+}
+
+// Utilization compares booked time against a reference, such as the
+// available time returned by Availability.GetSlots.
+type Utilization struct {
+	Start     time.Time
+	End       time.Time
+	Used      time.Duration
+	Available time.Duration
+	// Ratio is Used/Available, or 0 when nothing was available.
+	Ratio float64
+}
+
+// HistogramBin counts slots whose duration falls in [Min, Max). The last
+// bin has no upper limit and reports Max as zero.
+type HistogramBin struct {
+	Min   time.Duration
+	Max   time.Duration
+	Count int
+	Total time.Duration
+}
+
+// Histogram groups slot durations into bins split at the given edges,
+// e.g. Histogram(15*time.Minute, time.Hour) yields <15m, 15m-1h and >=1h.
+func (c SlotCollection) Histogram(edges ...time.Duration) []HistogramBin {
+	sorted := append([]time.Duration(nil), edges...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	bins := make([]HistogramBin, 0, len(sorted)+1)
+	var lower time.Duration
+	for _, e := range sorted {
+		if e <= lower {
+			continue
+		}
+		bins = append(bins, HistogramBin{Min: lower, Max: e})
+		lower = e
+	}
+	bins = append(bins, HistogramBin{Min: lower})
+	for _, s := range c.slots {
+		d := s.Duration()
+		i := sort.Search(len(bins)-1, func(i int) bool { return d < bins[i].Max })
+		bins[i].Count++
+		bins[i].Total += d
+	}
+	return bins
+}
+
+// Stats returns duration statistics for the whole collection.
+func (c SlotCollection) Stats() Stats {
+	return statsOf(c.slots)
+}
+
+// LongestSlot returns the slot with the greatest duration.
+func (c SlotCollection) LongestSlot() (TimeSlot, bool) {
+	if len(c.slots) == 0 {
+		return TimeSlot{}, false
+	}
+	longest := c.slots[0]
+	for _, s := range c.slots[1:] {
+		if s.Duration() > longest.Duration() {
+			longest = s
+		}
+	}
+	return longest, true
+}
+
+// Buckets splits the collection at period boundaries in loc and returns one
+// bucket per period between the first and last slot. Slots crossing a
+// boundary are divided between the buckets they touch.
+func (c SlotCollection) Buckets(period Period, loc *time.Location) []Bucket {
+	first, ok := c.First()
+	if !ok {
+		return nil
+	}
+	last, _ := c.Last()
+	return c.BucketsBetween(period, first.Start, last.End, loc)
+}
+
+// BucketsBetween returns one bucket per period overlapping [from, to), so
+// that empty periods are reported too.
+func (c SlotCollection) BucketsBetween(period Period, from, to time.Time, loc *time.Location) []Bucket {
+	if loc == nil {
+		loc = c.locationOrUTC()
+	}
+	var out []Bucket
+	for start := PeriodStart(from, period, loc); start.Before(to); {
+		end := nextPeriod(start, period, loc)
+		window := NewCollection(TimeSlot{Start: start, End: end, Location: loc})
+		slots := c.Intersect(window)
+		out = append(out, Bucket{Period: period, Start: start, End: end, Slots: slots, Stats: slots.Stats()})
+		start = end
+	}
+	return out
+}
+
+// UtilizationAgainst reports, per period, how much of reference is covered
+// by the collection.
+func (c SlotCollection) UtilizationAgainst(reference SlotCollection, period Period, loc *time.Location) []Utilization {
+	first, ok := reference.First()
+	if !ok {
+		return nil
+	}
+	last, _ := reference.Last()
+	used := c.Intersect(reference)
+	refBuckets := reference.BucketsBetween(period, first.Start, last.End, loc)
+	usedBuckets := used.BucketsBetween(period, first.Start, last.End, loc)
+	out := make([]Utilization, 0, len(refBuckets))
+	for i, b := range refBuckets {
+		u := Utilization{Start: b.Start, End: b.End, Used: usedBuckets[i].Stats.Total, Available: b.Stats.Total}
+		if u.Available > 0 {
+			u.Ratio = float64(u.Used) / float64(u.Available)
+		}
+		out = append(out, u)
+	}
+	return out
+}
+
+// PeriodStart returns the start of the period containing t in loc. Weeks
+// start on Monday.
+func PeriodStart(t time.Time, period Period, loc *time.Location) time.Time {
+	day := timeutil.StartOfDay(t, loc)
+	switch period {
+	case PeriodWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return timeutil.AddDays(day, -offset, loc)
+	case PeriodMonth:
+		return timeutil.AddDays(day, 1-day.Day(), loc)
+	default:
+		return day
+	}
+}
+
+func nextPeriod(start time.Time, period Period, loc *time.Location) time.Time {
+	switch period {
+	case PeriodWeek:
+		return timeutil.AddDays(start, 7, loc)
+	case PeriodMonth:
+		y, m, _ := start.In(loc).Date()
+		return timeutil.Date(y, m+1, 1, 0, 0, 0, 0, loc)
+	default:
+		return timeutil.AddDays(start, 1, loc)
+	}
+}
+
+func statsOf(slots []TimeSlot) Stats {
+	if len(slots) == 0 {
+		return Stats{}
+	}
+	st := Stats{Count: len(slots), Min: slots[0].Duration()}
+	for _, s := range slots {
+		d := s.Duration()
+		st.Total += d
+		if d < st.Min {
+			st.Min = d
+		}
+		if d > st.Max {
+			st.Max = d
+		}
+	}
+	st.Mean = st.Total / time.Duration(st.Count)
+	if st.Total > 0 {
+		st.Fragmentation = 1 - float64(st.Max)/float64(st.Total)
+	}
+	return st
+}
+
+func (c SlotCollection) locationOrUTC() *time.Location {
+	if c.location != nil {
+		return c.location
+	}
+	return time.UTC
+}
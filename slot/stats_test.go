@@ -0,0 +1,130 @@
+package slot
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCollectionStatsAndHistogram(t *testing.T) {
+	loc := time.UTC
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, loc)
+	c := NewCollection(
+		TimeSlot{Start: day.Add(9 * time.Hour), End: day.Add(9*time.Hour + 10*time.Minute), Location: loc},
+		TimeSlot{Start: day.Add(10 * time.Hour), End: day.Add(10*time.Hour + 30*time.Minute), Location: loc},
+		TimeSlot{Start: day.Add(12 * time.Hour), End: day.Add(14 * time.Hour), Location: loc},
+	)
+	st := c.Stats()
+	if st.Count != 3 || st.Total != 160*time.Minute || st.Min != 10*time.Minute || st.Max != 2*time.Hour {
+		t.Fatalf("unexpected stats: %+v", st)
+	}
+	if st.Mean != st.Total/3 {
+		t.Fatalf("unexpected mean: %v", st.Mean)
+	}
+	if want := 1 - 120.0/160.0; math.Abs(st.Fragmentation-want) > 1e-9 {
+		t.Fatalf("unexpected fragmentation: %v", st.Fragmentation)
+	}
+	if (NewCollection().Stats() != Stats{}) {
+		t.Fatalf("empty stats should be zero")
+	}
+	single := NewCollection(TimeSlot{Start: day, End: day.Add(time.Hour), Location: loc})
+	if single.Stats().Fragmentation != 0 {
+		t.Fatalf("single slot should not be fragmented")
+	}
+
+	longest, ok := c.LongestSlot()
+	if !ok || longest.Duration() != 2*time.Hour {
+		t.Fatalf("longest slot mismatch")
+	}
+	if _, ok := NewCollection().LongestSlot(); ok {
+		t.Fatalf("empty collection has no longest slot")
+	}
+
+	bins := c.Histogram(time.Hour, 15*time.Minute, time.Hour)
+	if len(bins) != 3 {
+		t.Fatalf("expected 3 bins, got %d", len(bins))
+	}
+	for i, want := range []int{1, 1, 1} {
+		if bins[i].Count != want {
+			t.Fatalf("bin %d count %d", i, bins[i].Count)
+		}
+	}
+	if bins[2].Max != 0 || bins[2].Min != time.Hour || bins[2].Total != 2*time.Hour {
+		t.Fatalf("unexpected open-ended bin: %+v", bins[2])
+	}
+}
+
+func TestCollectionBuckets(t *testing.T) {
+	loc := time.UTC
+	// Sunday 22:00 to Monday 02:00 crosses both a day and an ISO week boundary.
+	sunday := time.Date(2025, 1, 5, 22, 0, 0, 0, loc)
+	c := NewCollection(
+		TimeSlot{Start: sunday, End: sunday.Add(4 * time.Hour), Location: loc},
+		TimeSlot{Start: sunday.AddDate(0, 0, 3), End: sunday.AddDate(0, 0, 3).Add(time.Hour), Location: loc},
+	)
+
+	days := c.Buckets(PeriodDay, loc)
+	if len(days) != 4 {
+		t.Fatalf("expected 4 day buckets, got %d", len(days))
+	}
+	if days[0].Stats.Total != 2*time.Hour || days[1].Stats.Total != 2*time.Hour || days[2].Stats.Total != 0 {
+		t.Fatalf("unexpected day totals: %v %v %v", days[0].Stats.Total, days[1].Stats.Total, days[2].Stats.Total)
+	}
+
+	weeks := c.Buckets(PeriodWeek, loc)
+	if len(weeks) != 2 || weeks[1].Start.Weekday() != time.Monday {
+		t.Fatalf("unexpected week buckets: %d", len(weeks))
+	}
+	if weeks[1].Stats.Total != 3*time.Hour {
+		t.Fatalf("unexpected week total %v", weeks[1].Stats.Total)
+	}
+
+	months := c.Buckets(PeriodMonth, loc)
+	if len(months) != 1 || months[0].Start.Day() != 1 || months[0].Period.String() != "month" {
+		t.Fatalf("unexpected month buckets")
+	}
+	if NewCollection().Buckets(PeriodDay, nil) != nil {
+		t.Fatalf("empty collection has no buckets")
+	}
+	if Period(9).String() != "unknown" || PeriodDay.String() != "day" || PeriodWeek.String() != "week" {
+		t.Fatalf("unexpected period strings")
+	}
+}
+
+func TestBucketsFollowLocalDays(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skip("timezone DB unavailable")
+	}
+	// 20:00-22:00 UTC on Jan 6 is 01:30-03:30 on Jan 7 in Kolkata.
+	s := TimeSlot{Start: time.Date(2025, 1, 6, 20, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 6, 22, 0, 0, 0, time.UTC), Location: time.UTC}
+	days := NewCollection(s).Buckets(PeriodDay, kolkata)
+	if len(days) != 1 || days[0].Start.In(kolkata).Day() != 7 {
+		t.Fatalf("expected a single Kolkata Jan 7 bucket, got %v", days)
+	}
+}
+
+func TestUtilizationAgainst(t *testing.T) {
+	loc := time.UTC
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, loc)
+	available := NewCollection(
+		TimeSlot{Start: monday.Add(9 * time.Hour), End: monday.Add(17 * time.Hour), Location: loc},
+		TimeSlot{Start: monday.AddDate(0, 0, 2).Add(9 * time.Hour), End: monday.AddDate(0, 0, 2).Add(17 * time.Hour), Location: loc},
+	)
+	booked := NewCollection(
+		TimeSlot{Start: monday.Add(8 * time.Hour), End: monday.Add(11 * time.Hour), Location: loc},
+	)
+	report := booked.UtilizationAgainst(available, PeriodDay, loc)
+	if len(report) != 3 {
+		t.Fatalf("expected 3 days, got %d", len(report))
+	}
+	if report[0].Used != 2*time.Hour || report[0].Available != 8*time.Hour || report[0].Ratio != 0.25 {
+		t.Fatalf("unexpected monday utilization: %+v", report[0])
+	}
+	if report[1].Available != 0 || report[1].Ratio != 0 {
+		t.Fatalf("empty day should have zero ratio: %+v", report[1])
+	}
+	if booked.UtilizationAgainst(NewCollection(), PeriodDay, loc) != nil {
+		t.Fatalf("empty reference should produce no report")
+	}
+}